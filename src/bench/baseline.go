@@ -0,0 +1,103 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the file in the cache directory which holds the baselines.
+const FileName = "bench.json"
+
+// Baselines holds the last result of every benchmark, by key.
+type Baselines map[string]*Result
+
+// Key identifies a benchmark, results are only compared when rendering the same config in the same way.
+func Key(config, pwd, fixture string) string {
+	if len(fixture) != 0 {
+		return fmt.Sprintf("%s|fixture:%s", config, fixture)
+	}
+
+	return fmt.Sprintf("%s|%s", config, pwd)
+}
+
+// LoadBaselines reads the baselines in dir, a missing file results in no baselines.
+func LoadBaselines(dir string) (Baselines, error) {
+	baselines := make(Baselines)
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return baselines, nil
+	}
+
+	if err != nil {
+		return baselines, err
+	}
+
+	if err := json.Unmarshal(data, &baselines); err != nil {
+		return make(Baselines), err
+	}
+
+	return baselines, nil
+}
+
+// Save writes the baselines to dir.
+func (b Baselines) Save(dir string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, FileName), data, 0o644)
+}
+
+// Regression is a value which got worse than the threshold allows.
+type Regression struct {
+	Metric   string
+	Previous float64
+	Current  float64
+}
+
+func (r *Regression) String() string {
+	unit := func(value float64) string {
+		if r.Metric == "allocations" {
+			return fmt.Sprintf("%.0f", value)
+		}
+
+		return formatDuration(time.Duration(value))
+	}
+
+	return fmt.Sprintf("%s: %s → %s (%s)", r.Metric, unit(r.Previous), unit(r.Current), change(r.Previous, r.Current))
+}
+
+// Regressions compares the totals and allocations of current to previous,
+// returning every value which increased by more than threshold percent.
+func Regressions(previous, current *Result, threshold float64) []*Regression {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	metrics := []*Regression{
+		{Metric: "total p50", Previous: float64(previous.Total.P50), Current: float64(current.Total.P50)},
+		{Metric: "total p95", Previous: float64(previous.Total.P95), Current: float64(current.Total.P95)},
+		{Metric: "allocations", Previous: float64(previous.Allocs), Current: float64(current.Allocs)},
+	}
+
+	var regressions []*Regression
+
+	for _, metric := range metrics {
+		if metric.Previous == 0 {
+			continue
+		}
+
+		if (metric.Current-metric.Previous)/metric.Previous*100 > threshold {
+			regressions = append(regressions, metric)
+		}
+	}
+
+	return regressions
+}
@@ -0,0 +1,204 @@
+package bench
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sample is the measurement of a single prompt render.
+type Sample struct {
+	// Segments holds the time spent executing every segment, by name.
+	Segments map[string]time.Duration
+	// Startup is the time spent loading the config and setting up the engine.
+	Startup time.Duration
+	// Render is the time spent rendering the prompt.
+	Render time.Duration
+	// Allocs is the number of heap allocations made.
+	Allocs uint64
+	// Bytes is the number of bytes allocated on the heap.
+	Bytes uint64
+}
+
+// Stats are the percentiles of a series of durations.
+type Stats struct {
+	P50 time.Duration `json:"p50"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+}
+
+// Result summarizes the samples of a benchmark run.
+type Result struct {
+	Date     time.Time        `json:"date"`
+	Segments map[string]Stats `json:"segments,omitempty"`
+	Version  string           `json:"version,omitempty"`
+	Startup  Stats            `json:"startup"`
+	Render   Stats            `json:"render"`
+	Total    Stats            `json:"total"`
+	Count    int              `json:"count"`
+	Allocs   uint64           `json:"allocs"`
+	Bytes    uint64           `json:"bytes"`
+}
+
+// Summarize computes the percentiles of the samples, allocations are averaged per render.
+func Summarize(samples []*Sample) *Result {
+	result := &Result{
+		Date:     time.Now(),
+		Count:    len(samples),
+		Segments: make(map[string]Stats),
+	}
+
+	if len(samples) == 0 {
+		return result
+	}
+
+	var startup, render, total []time.Duration
+	segments := make(map[string][]time.Duration)
+
+	for _, sample := range samples {
+		startup = append(startup, sample.Startup)
+		render = append(render, sample.Render)
+		total = append(total, sample.Startup+sample.Render)
+
+		result.Allocs += sample.Allocs
+		result.Bytes += sample.Bytes
+
+		for name, duration := range sample.Segments {
+			segments[name] = append(segments[name], duration)
+		}
+	}
+
+	result.Startup = stats(startup)
+	result.Render = stats(render)
+	result.Total = stats(total)
+	result.Allocs /= uint64(len(samples))
+	result.Bytes /= uint64(len(samples))
+
+	for name, durations := range segments {
+		result.Segments[name] = stats(durations)
+	}
+
+	return result
+}
+
+func stats(durations []time.Duration) Stats {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	return Stats{
+		P50: percentile(sorted, 50),
+		P95: percentile(sorted, 95),
+		P99: percentile(sorted, 99),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	rank = max(rank, 1)
+
+	return sorted[rank-1]
+}
+
+// Report prints the result, when previous is set every value is compared to it.
+func (r *Result) Report(previous *Result) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%d renders\n\n", r.Count)
+	fmt.Fprintf(&builder, "%-24s %10s %10s %10s\n", "", "p50", "p95", "p99")
+
+	line := func(name string, current Stats, before *Stats) {
+		fmt.Fprintf(&builder, "%-24s %10s %10s %10s\n", name, formatDuration(current.P50), formatDuration(current.P95), formatDuration(current.P99))
+
+		if before == nil {
+			return
+		}
+
+		fmt.Fprintf(&builder, "%-24s %10s %10s %10s\n", "",
+			change(float64(before.P50), float64(current.P50)),
+			change(float64(before.P95), float64(current.P95)),
+			change(float64(before.P99), float64(current.P99)))
+	}
+
+	var startup, render, total *Stats
+	var segments map[string]Stats
+
+	if previous != nil {
+		startup, render, total = &previous.Startup, &previous.Render, &previous.Total
+		segments = previous.Segments
+	}
+
+	line("startup", r.Startup, startup)
+	line("render", r.Render, render)
+	line("total", r.Total, total)
+
+	fmt.Fprintf(&builder, "\n%-24s %10d", "allocations", r.Allocs)
+	if previous != nil {
+		fmt.Fprintf(&builder, " %10s", change(float64(previous.Allocs), float64(r.Allocs)))
+	}
+
+	fmt.Fprintf(&builder, "\n%-24s %10s", "allocated", formatBytes(r.Bytes))
+	if previous != nil {
+		fmt.Fprintf(&builder, " %10s", change(float64(previous.Bytes), float64(r.Bytes)))
+	}
+
+	builder.WriteString("\n")
+
+	if len(r.Segments) == 0 {
+		return builder.String()
+	}
+
+	fmt.Fprintf(&builder, "\nSegments:\n\n%-24s %10s %10s %10s\n", "", "p50", "p95", "p99")
+
+	names := make([]string, 0, len(r.Segments))
+	for name := range r.Segments {
+		names = append(names, name)
+	}
+
+	// the slowest segments first
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(r.Segments[b].P50, r.Segments[a].P50), strings.Compare(a, b))
+	})
+
+	for _, name := range names {
+		var segmentBefore *Stats
+		if stats, ok := segments[name]; ok {
+			segmentBefore = &stats
+		}
+
+		line(name, r.Segments[name], segmentBefore)
+	}
+
+	return builder.String()
+}
+
+func change(before, after float64) string {
+	if before == 0 {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%+.1f%%", (after-before)/before*100)
+}
+
+func formatDuration(duration time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(duration.Microseconds())/1000)
+}
+
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+
+	switch {
+	case bytes >= unit*unit:
+		return fmt.Sprintf("%.1fMB", float64(bytes)/(unit*unit))
+	case bytes >= unit:
+		return fmt.Sprintf("%.1fKB", float64(bytes)/unit)
+	default:
+		return fmt.Sprintf("%dB", bytes)
+	}
+}
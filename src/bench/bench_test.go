@@ -0,0 +1,112 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := make([]time.Duration, 0, 100)
+	for i := 1; i <= 100; i++ {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	cases := []struct {
+		Case      string
+		Durations []time.Duration
+		P         int
+		Expected  time.Duration
+	}{
+		{Case: "No durations", P: 50},
+		{Case: "Single duration", Durations: []time.Duration{time.Second}, P: 99, Expected: time.Second},
+		{Case: "Median", Durations: durations, P: 50, Expected: 50 * time.Millisecond},
+		{Case: "p95", Durations: durations, P: 95, Expected: 95 * time.Millisecond},
+		{Case: "p99 of few", Durations: durations[:10], P: 99, Expected: 10 * time.Millisecond},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.Expected, percentile(tc.Durations, tc.P), tc.Case)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	samples := []*Sample{
+		{Startup: 2 * time.Millisecond, Render: 10 * time.Millisecond, Allocs: 100, Bytes: 1000, Segments: map[string]time.Duration{"Git": 8 * time.Millisecond}},
+		{Startup: time.Millisecond, Render: 20 * time.Millisecond, Allocs: 300, Bytes: 3000, Segments: map[string]time.Duration{"Git": 16 * time.Millisecond}},
+	}
+
+	result := Summarize(samples)
+
+	assert.Equal(t, 2, result.Count)
+	assert.Equal(t, uint64(200), result.Allocs)
+	assert.Equal(t, uint64(2000), result.Bytes)
+	assert.Equal(t, time.Millisecond, result.Startup.P50)
+	assert.Equal(t, 12*time.Millisecond, result.Total.P50)
+	assert.Equal(t, 21*time.Millisecond, result.Total.P99)
+	assert.Equal(t, 16*time.Millisecond, result.Segments["Git"].P95)
+}
+
+func TestRegressions(t *testing.T) {
+	previous := &Result{Total: Stats{P50: 10 * time.Millisecond, P95: 20 * time.Millisecond}, Allocs: 1000}
+
+	cases := []struct {
+		Previous  *Result
+		Current   *Result
+		Case      string
+		Expected  []string
+		Threshold float64
+	}{
+		{Case: "No baseline", Current: previous, Threshold: 10},
+		{Case: "Unchanged", Previous: previous, Current: previous, Threshold: 10},
+		{
+			Case:      "Within threshold",
+			Previous:  previous,
+			Current:   &Result{Total: Stats{P50: 10500 * time.Microsecond, P95: 21 * time.Millisecond}, Allocs: 1050},
+			Threshold: 10,
+		},
+		{
+			Case:      "Slower",
+			Previous:  previous,
+			Current:   &Result{Total: Stats{P50: 15 * time.Millisecond, P95: 20 * time.Millisecond}, Allocs: 1000},
+			Threshold: 10,
+			Expected:  []string{"total p50: 10.00ms → 15.00ms (+50.0%)"},
+		},
+		{
+			Case:      "More allocations",
+			Previous:  previous,
+			Current:   &Result{Total: previous.Total, Allocs: 1200},
+			Threshold: 10,
+			Expected:  []string{"allocations: 1000 → 1200 (+20.0%)"},
+		},
+	}
+
+	for _, tc := range cases {
+		var got []string
+		for _, regression := range Regressions(tc.Previous, tc.Current, tc.Threshold) {
+			got = append(got, regression.String())
+		}
+
+		assert.Equal(t, tc.Expected, got, tc.Case)
+	}
+}
+
+func TestBaselines(t *testing.T) {
+	dir := t.TempDir()
+
+	baselines, err := LoadBaselines(dir)
+	require.NoError(t, err)
+	assert.Empty(t, baselines)
+
+	key := Key("theme.omp.json", "/home/posh", "")
+	baselines[key] = &Result{Count: 20, Total: Stats{P50: time.Millisecond}}
+	require.NoError(t, baselines.Save(dir))
+
+	loaded, err := LoadBaselines(dir)
+	require.NoError(t, err)
+	require.Contains(t, loaded, key)
+	assert.Equal(t, time.Millisecond, loaded[key].Total.P50)
+	assert.NotContains(t, loaded, Key("theme.omp.json", "/home/posh", "fixture.json"))
+}
@@ -11,6 +11,7 @@ func init() {
 	gob.Register(SimpleTemplate{})
 	gob.Register((*Duration)(nil))
 	gob.Register(map[string]bool{})
+	gob.Register(map[string]int{})
 }
 
 const (
@@ -23,12 +24,34 @@ const (
 	PROMPTCOUNTCACHE = "prompt_count_cache"
 	ENGINECACHE      = "engine_cache"
 	FONTLISTCACHE    = "font_list_cache"
+	OVERRUNCACHE     = "segment_overrun_cache"
 )
 
 type Entry[T any] struct {
 	Value     T
 	Timestamp int64
 	TTL       int
+	// Modified is the time of the last change in nanoseconds,
+	// used to decide which shell's value wins when merging.
+	Modified int64
+}
+
+// Expires returns when the entry expires, the zero time when it never does.
+func (c *Entry[T]) Expires() time.Time {
+	if c.TTL < 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(c.Timestamp+int64(c.TTL), 0)
+}
+
+func (c *Entry[T]) modified() int64 {
+	if c.Modified != 0 {
+		return c.Modified
+	}
+
+	// entries written before we tracked changes
+	return c.Timestamp * int64(time.Second)
 }
 
 func (c *Entry[T]) Expired() bool {
@@ -39,7 +39,7 @@ func Clear(force bool, excludedFiles ...string) error {
 			return true
 		}
 
-		return strings.EqualFold(fileName, DeviceStore) || strings.HasPrefix(fileName, "init.")
+		return strings.EqualFold(fileName, DeviceStore) || strings.EqualFold(fileName, DeviceStore+lockExtension) || strings.HasPrefix(fileName, "init.")
 	}
 
 	if len(excludedFiles) > 0 {
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockExtension     = ".lock"
+	lockRetryInterval = 5 * time.Millisecond
+	lockTimeout       = 500 * time.Millisecond
+)
+
+// lock takes an exclusive lock on a file next to the cache file, so only one shell at a time
+// reads and writes it. We give up after a short while as we'd rather lose a cache update than
+// keep the prompt waiting. The returned function releases the lock.
+func lock(filePath string) (func(), error) {
+	file, err := os.OpenFile(filePath+lockExtension, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		if locked {
+			return func() {
+				_ = unlock(file)
+				file.Close()
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("timeout waiting for lock on %s", filePath)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -3,8 +3,12 @@ package cache
 import (
 	"encoding/gob"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/log"
@@ -12,10 +16,15 @@ import (
 )
 
 type store struct {
-	cache    *maps.Concurrent[*Entry[any]]
+	cache *maps.Concurrent[*Entry[any]]
+	// changes holds the time we last set or deleted a key, so we only
+	// write our own changes when merging with what other shells stored
+	changes  *maps.Concurrent[int64]
 	filePath string
-	dirty    bool
-	persist  bool
+	// cleared is the time all keys were deleted
+	cleared atomic.Int64
+	dirty   bool
+	persist bool
 }
 
 var (
@@ -33,7 +42,8 @@ const (
 
 func (s Store) new() *store {
 	return &store{
-		cache: maps.NewConcurrent[*Entry[any]](),
+		cache:   maps.NewConcurrent[*Entry[any]](),
+		changes: maps.NewConcurrent[int64](),
 	}
 }
 
@@ -61,9 +71,18 @@ func (s Store) init(filePath string, persist bool) {
 
 	store := s.get()
 	store.cache = maps.NewConcurrent[*Entry[any]]()
+	store.changes = maps.NewConcurrent[int64]()
 	store.filePath = filepath.Join(Path(), filePath)
 	store.persist = persist
 
+	unlock, err := lock(store.filePath)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	defer unlock()
+
 	reader, err := openFile(store.filePath)
 	if err != nil {
 		// set to dirty so we create it on close
@@ -74,10 +93,8 @@ func (s Store) init(filePath string, persist bool) {
 
 	defer reader.Close()
 
-	var list maps.Simple[*Entry[any]]
-
-	dec := gob.NewDecoder(reader)
-	if err := dec.Decode(&list); err != nil {
+	list, err := decode(reader)
+	if err != nil {
 		log.Error(err)
 		// If gob decoding fails, the cache file might be from the old format
 		// Set dirty to true so we recreate it in gob format
@@ -105,20 +122,103 @@ func (s Store) close() {
 		return
 	}
 
-	cache := store.cache.ToSimple()
+	if err := store.write(); err != nil {
+		log.Error(err)
+	}
+}
 
-	file, err := openFile(store.filePath)
+// write stores our changes in the cache file. As other shells can have written to the file
+// since we read it, we read it again while holding the lock and merge the changes.
+func (s *store) write() error {
+	unlock, err := lock(s.filePath)
 	if err != nil {
-		log.Error(err)
-		return
+		return err
+	}
+
+	defer unlock()
+
+	file, err := openFile(s.filePath)
+	if err != nil {
+		return err
 	}
 
 	defer file.Close()
 
+	stored, err := decode(file)
+	if err != nil {
+		log.Debugf("unable to read the stored cache, overwriting it: %s", err)
+	}
+
+	cache := s.merge(stored)
+
+	// make sure we don't leave any of the previous content behind
+	if f, OK := file.(*os.File); OK {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
 	enc := gob.NewEncoder(file)
 	if err := enc.Encode(cache); err != nil {
-		log.Error(err)
+		return err
 	}
+
+	// our changes are stored now, later changes by others should win
+	s.changes = maps.NewConcurrent[int64]()
+	s.dirty = false
+
+	return nil
+}
+
+func decode(reader io.Reader) (maps.Simple[*Entry[any]], error) {
+	var list maps.Simple[*Entry[any]]
+
+	dec := gob.NewDecoder(reader)
+	if err := dec.Decode(&list); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// merge combines the stored entries with the ones we changed, for every key the
+// most recent change wins. Entries we didn't touch are kept as they are stored.
+func (s *store) merge(stored maps.Simple[*Entry[any]]) maps.Simple[*Entry[any]] {
+	result := make(maps.Simple[*Entry[any]], len(stored))
+	cleared := s.cleared.Load()
+
+	for key, entry := range stored {
+		if entry == nil || entry.Expired() || entry.modified() <= cleared {
+			continue
+		}
+
+		result[key] = entry
+	}
+
+	for key, changed := range s.changes.ToSimple() {
+		if current, OK := result[key]; OK && current.modified() > changed {
+			log.Debugf("keeping the more recent stored value for key: %s", key)
+			continue
+		}
+
+		entry, OK := s.cache.Get(key)
+		if !OK || entry.Expired() {
+			delete(result, key)
+			continue
+		}
+
+		result[key] = entry
+	}
+
+	return result
 }
 
 // Get retrieves a typed value from the specified store
@@ -172,12 +272,16 @@ func Set[T any](s Store, key string, value T, duration Duration) {
 
 	log.Debugf("(%s) setting entry: %s - %v with duration: %s", string(s), key, value, string(duration))
 
+	now := time.Now()
+
 	store.cache.Set(key, &Entry[any]{
 		Value:     value,
-		Timestamp: time.Now().Unix(),
+		Timestamp: now.Unix(),
 		TTL:       seconds,
+		Modified:  now.UnixNano(),
 	})
 
+	store.changes.Set(key, now.UnixNano())
 	store.dirty = true
 }
 
@@ -193,6 +297,7 @@ func Delete(s Store, key string) {
 
 	log.Debugf("(%s) deleting key: %s", string(s), key)
 	store.cache.Delete(key)
+	store.changes.Set(key, time.Now().UnixNano())
 	store.dirty = true
 }
 
@@ -206,10 +311,34 @@ func DeleteAll(s Store) {
 	}
 
 	store.cache = maps.NewConcurrent[*Entry[any]]()
+	store.changes = maps.NewConcurrent[int64]()
+	store.cleared.Store(time.Now().UnixNano())
 	store.dirty = true
 }
 
-func Print(s Store) string {
+// Entries returns all entries in the store whose key starts with the given prefix.
+func Entries(s Store, prefix string) maps.Simple[*Entry[any]] {
+	defer log.Trace(time.Now(), string(s), prefix)
+
+	entries := make(maps.Simple[*Entry[any]])
+
+	store := s.get()
+	if store == nil {
+		return entries
+	}
+
+	for key, entry := range store.cache.ToSimple() {
+		if strings.HasPrefix(key, prefix) {
+			entries[key] = entry
+		}
+	}
+
+	return entries
+}
+
+// Print returns a readable overview of the entries whose key starts with the given prefix.
+// The owner function, when set, describes who stored the entry.
+func Print(s Store, prefix string, owner func(key string) string) string {
 	defer log.Trace(time.Now(), string(s))
 
 	store := s.get()
@@ -217,14 +346,23 @@ func Print(s Store) string {
 		return fmt.Sprintf("Store %s is nil", string(s))
 	}
 
-	cache := store.cache.ToSimple()
+	cache := Entries(s, prefix)
 	if len(cache) == 0 {
 		return fmt.Sprintf("Store %s is empty", string(s))
 	}
 
 	var builder strings.Builder
 
-	for key, entry := range cache {
+	keys := make([]string, 0, len(cache))
+	for key := range cache {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		entry := cache[key]
+
 		builder.WriteString("\n")
 
 		if entry.Expired() {
@@ -238,8 +376,7 @@ func Print(s Store) string {
 			ttlInfo = "never expires"
 		}
 		if entry.TTL >= 0 {
-			expiresAt := time.Unix(entry.Timestamp+int64(entry.TTL), 0)
-			ttlInfo = fmt.Sprintf("expires at %s", expiresAt.Format("2006-01-02 15:04:05"))
+			ttlInfo = fmt.Sprintf("expires at %s", entry.Expires().Format("2006-01-02 15:04:05"))
 		}
 
 		builder.WriteString(fmt.Sprintf("Key: %s\n", key))
@@ -247,6 +384,14 @@ func Print(s Store) string {
 		builder.WriteString(fmt.Sprintf("  Type: %T\n", entry.Value))
 		builder.WriteString(fmt.Sprintf("  Created: %s\n", time.Unix(entry.Timestamp, 0).Format("2006-01-02 15:04:05")))
 		builder.WriteString(fmt.Sprintf("  TTL: %s\n", ttlInfo))
+
+		if owner == nil {
+			continue
+		}
+
+		if description := owner(key); len(description) != 0 {
+			builder.WriteString(fmt.Sprintf("  Owner: %s\n", description))
+		}
 	}
 
 	return builder.String()
@@ -1,11 +1,18 @@
 package cache
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jandedobbeleer/oh-my-posh/src/maps"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStore(t *testing.T) {
@@ -37,7 +44,7 @@ func TestStore(t *testing.T) {
 				return testStore
 			},
 			testFunc: func(t *testing.T) {
-				result := Print(Session)
+				result := Print(Session, "", nil)
 				assert.Contains(t, result, "Key: test_key1")
 				assert.Contains(t, result, `Value: "test_value1"`) // Note: quotes are included in output
 				assert.Contains(t, result, "Type: string")
@@ -53,6 +60,38 @@ func TestStore(t *testing.T) {
 				assert.True(t, len(lines) > 10, "Output should have multiple lines")
 			},
 		},
+		{
+			name: "Print store with prefix and owner",
+			setupFunc: func() *store {
+				testStore := Session.new()
+				testStore.cache.Set("segment_cache_Git", &Entry[any]{
+					Value:     "git",
+					Timestamp: time.Now().Unix(),
+					TTL:       3600,
+				})
+				testStore.cache.Set("other_key", &Entry[any]{
+					Value:     "other",
+					Timestamp: time.Now().Unix(),
+					TTL:       3600,
+				})
+				session = testStore
+				return testStore
+			},
+			testFunc: func(t *testing.T) {
+				owner := func(key string) string {
+					return "owner of " + key
+				}
+
+				result := Print(Session, "segment_", owner)
+				assert.Contains(t, result, "Key: segment_cache_Git")
+				assert.Contains(t, result, "Owner: owner of segment_cache_Git")
+				assert.NotContains(t, result, "other_key")
+
+				entries := Entries(Session, "other")
+				assert.Len(t, entries, 1)
+				assert.Contains(t, entries, "other_key")
+			},
+		},
 		{
 			name: "Print empty store",
 			setupFunc: func() *store {
@@ -61,7 +100,7 @@ func TestStore(t *testing.T) {
 				return testStore
 			},
 			testFunc: func(t *testing.T) {
-				result := Print(Session)
+				result := Print(Session, "", nil)
 				assert.Contains(t, result, "Store session is empty")
 			},
 		},
@@ -74,7 +113,7 @@ func TestStore(t *testing.T) {
 			},
 			testFunc: func(t *testing.T) {
 				// Since get() always creates a store, we test empty store behavior
-				result := Print(Session)
+				result := Print(Session, "", nil)
 				assert.Contains(t, result, "Store session is empty")
 			},
 		},
@@ -87,3 +126,131 @@ func TestStore(t *testing.T) {
 		})
 	}
 }
+
+func newTestStore(filePath string) *store {
+	testStore := Device.new()
+	testStore.filePath = filePath
+	testStore.persist = true
+
+	return testStore
+}
+
+func (s *store) setTestEntry(key string, value any, modified int64) {
+	s.cache.Set(key, &Entry[any]{
+		Value:     value,
+		Timestamp: time.Now().Unix(),
+		TTL:       3600,
+		Modified:  modified,
+	})
+
+	s.changes.Set(key, modified)
+	s.dirty = true
+}
+
+func readTestStore(t *testing.T, filePath string) maps.Simple[*Entry[any]] {
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	list, err := decode(file)
+	require.NoError(t, err)
+
+	return list
+}
+
+func TestStoreConcurrentWriters(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), DeviceStore)
+
+	const writers = 10
+
+	var wg sync.WaitGroup
+
+	for i := range writers {
+		writer := newTestStore(filePath)
+		writer.setTestEntry(fmt.Sprintf("writer_%d", i), i, time.Now().UnixNano())
+		writer.setTestEntry("shared", i, int64(i+1))
+
+		wg.Go(func() {
+			assert.NoError(t, writer.write())
+		})
+	}
+
+	wg.Wait()
+
+	list := readTestStore(t, filePath)
+
+	for i := range writers {
+		entry, OK := list[fmt.Sprintf("writer_%d", i)]
+		if assert.True(t, OK, "entry of writer %d is missing", i) {
+			assert.Equal(t, i, entry.Value)
+		}
+	}
+
+	assert.Equal(t, writers-1, list["shared"].Value, "the most recent change should win")
+}
+
+func TestStoreMerge(t *testing.T) {
+	cases := []struct {
+		setup    func(first, second *store)
+		expected map[string]any
+		name     string
+	}{
+		{
+			name: "keep entries of both stores",
+			setup: func(first, second *store) {
+				first.setTestEntry("first", "a", 1)
+				second.setTestEntry("second", "b", 2)
+			},
+			expected: map[string]any{"first": "a", "second": "b"},
+		},
+		{
+			name: "a late write of an older change loses",
+			setup: func(first, second *store) {
+				second.setTestEntry("key", "newer", 2)
+				first.setTestEntry("key", "older", 1)
+			},
+			expected: map[string]any{"key": "newer"},
+		},
+		{
+			name: "deleted keys are not restored by the other store",
+			setup: func(first, second *store) {
+				first.setTestEntry("key", "value", 1)
+				require.NoError(t, first.write())
+
+				second.cache.Set("key", first.cache.MustGet("key"))
+				first.setTestEntry("other", "value", 2)
+
+				second.cache.Delete("key")
+				second.changes.Set("key", 3)
+				second.dirty = true
+			},
+			expected: map[string]any{"other": "value"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), DeviceStore)
+
+			first := newTestStore(filePath)
+			second := newTestStore(filePath)
+
+			tc.setup(first, second)
+
+			// the second store always writes first, simulating a shell finishing its prompt earlier
+			require.NoError(t, second.write())
+			require.NoError(t, first.write())
+
+			list := readTestStore(t, filePath)
+			assert.Len(t, list, len(tc.expected))
+
+			for key, value := range tc.expected {
+				entry, OK := list[key]
+				if assert.True(t, OK, key) {
+					assert.Equal(t, value, entry.Value, key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	stdruntime "runtime"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/bench"
+	"github.com/jandedobbeleer/oh-my-posh/src/build"
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/config"
+	"github.com/jandedobbeleer/oh-my-posh/src/profile"
+	"github.com/jandedobbeleer/oh-my-posh/src/prompt"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/fixture"
+	"github.com/jandedobbeleer/oh-my-posh/src/shell"
+	"github.com/jandedobbeleer/oh-my-posh/src/template"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchCount        int
+	benchFixture      string
+	benchThreshold    float64
+	benchSaveBaseline bool
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the primary prompt",
+	Long: `Benchmark the primary prompt.
+
+Renders the primary prompt of the config the given number of times and reports the percentiles
+of the startup and render time, the allocations and the time spent by every segment.
+
+The first result is stored in the cache directory as the baseline, every next run for the same
+config and directory is compared to it and exits with an error when it is slower than the threshold allows.
+Use --save-baseline to replace the baseline with the result of this run.
+Use --fixture to replay an environment recorded with oh-my-posh debug --record
+instead of running the actual commands.`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if benchCount <= 0 {
+			fmt.Println("count must be greater than 0")
+			exitcode = 2
+			return
+		}
+
+		var recorded *fixture.Fixture
+		if len(benchFixture) != 0 {
+			var err error
+			if recorded, err = fixture.Load(benchFixture); err != nil {
+				fmt.Println("unable to load the fixture:", err)
+				exitcode = 1
+				return
+			}
+		}
+
+		cache.Init(shell.GENERIC)
+		defer cache.Close()
+
+		// the first render fills the caches, just like the prompt after starting a shell
+		benchRender(recorded)
+
+		samples := make([]*bench.Sample, 0, benchCount)
+		for range benchCount {
+			samples = append(samples, benchRender(recorded))
+		}
+
+		result := bench.Summarize(samples)
+		result.Version = build.Version
+
+		baselines, err := bench.LoadBaselines(cache.Path())
+		if err != nil {
+			fmt.Println("unable to read the previous results:", err)
+		}
+
+		dir := pwd
+		if len(dir) == 0 {
+			dir, _ = os.Getwd()
+		}
+
+		key := bench.Key(configFlag, dir, benchFixture)
+		previous := baselines[key]
+
+		fmt.Print(result.Report(previous))
+
+		// a regression should keep on failing until it's fixed, or accepted as the new baseline
+		if previous == nil || benchSaveBaseline {
+			baselines[key] = result
+			if err := baselines.Save(cache.Path()); err != nil {
+				fmt.Println("unable to store the results:", err)
+			}
+		}
+
+		if previous == nil {
+			return
+		}
+
+		regressions := bench.Regressions(previous, result, benchThreshold)
+		if len(regressions) == 0 {
+			fmt.Printf("\nNo regressions compared to %s\n", previous.Date.Format(time.DateTime))
+			return
+		}
+
+		fmt.Printf("\nRegressions compared to %s:\n\n", previous.Date.Format(time.DateTime))
+		for _, regression := range regressions {
+			fmt.Println(regression)
+		}
+
+		exitcode = 1
+	},
+}
+
+// benchRender measures a single primary prompt, from loading the config up until the prompt is rendered.
+func benchRender(recorded *fixture.Fixture) *bench.Sample {
+	// every prompt is a new process, which starts without templates
+	template.Cache = nil
+
+	var before, after stdruntime.MemStats
+	stdruntime.ReadMemStats(&before)
+
+	start := time.Now()
+
+	var eng *prompt.Engine
+	if recorded != nil {
+		cfg := config.Load(configFlag, false)
+		eng = prompt.NewFromEnvironment(fixture.NewReplayer(recorded), cfg)
+	} else {
+		eng = prompt.New(&runtime.Flags{
+			ConfigPath: configFlag,
+			PWD:        pwd,
+			Shell:      shell.GENERIC,
+			Plain:      true,
+			Type:       prompt.PRIMARY,
+			IsPrimary:  true,
+		})
+	}
+
+	eng.Profile = profile.New()
+
+	startup := time.Since(start)
+	start = time.Now()
+
+	_ = eng.Primary()
+
+	render := time.Since(start)
+
+	stdruntime.ReadMemStats(&after)
+
+	sample := &bench.Sample{
+		Startup:  startup,
+		Render:   render,
+		Allocs:   after.Mallocs - before.Mallocs,
+		Bytes:    after.TotalAlloc - before.TotalAlloc,
+		Segments: make(map[string]time.Duration),
+	}
+
+	for _, span := range eng.Profile.Spans() {
+		if span.Category == profile.Segment {
+			sample.Segments[span.Segment] += span.Duration
+		}
+	}
+
+	return sample
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&pwd, "pwd", "", "current working directory")
+	benchCmd.Flags().IntVarP(&benchCount, "count", "n", 20, "number of renders")
+	benchCmd.Flags().StringVar(&benchFixture, "fixture", "", "replay the environment recorded in this fixture file")
+	benchCmd.Flags().Float64Var(&benchThreshold, "threshold", 10, "percentage a value can increase before it is reported as a regression")
+	benchCmd.Flags().BoolVar(&benchSaveBaseline, "save-baseline", false, "store the result of this run as the baseline to compare to")
+
+	RootCmd.AddCommand(benchCmd)
+}
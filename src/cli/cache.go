@@ -1,22 +1,30 @@
 package cli
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/config"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	session bool
+	session       bool
+	keyPrefix     string
+	jsonOutput    bool
+	cacheDuration string
 )
 
 // cacheCmd represents the cache command
 var cacheCmd = &cobra.Command{
-	Use:   "cache [path|clear|ttl|show]",
+	Use:   "cache [path|clear|ttl|show|get|set|delete|prune]",
 	Short: "Interact with the oh-my-posh cache",
 	Long: `Interact with the oh-my-posh cache.
 
@@ -25,14 +33,24 @@ You can do the following:
 - path: list cache path
 - clear: remove all cache values
 - ttl: get cache TTL in days
-- show: print a detailed list of all cached values`,
+- show: print a detailed list of all cached values
+- get <key>: print the value of a key
+- set <key> <value>: store a value for a key
+- delete [key]: remove a key, or all keys matching --prefix
+- prune: remove the values of segments that are no longer in your config
+
+The device cache is used by default, use --session for the current session's cache.`,
 	ValidArgs: []string{
 		"path",
 		"clear",
 		cache.TTL,
 		"show",
+		"get",
+		"set",
+		"delete",
+		"prune",
 	},
-	Args: cobra.RangeArgs(1, 2),
+	Args: cobra.RangeArgs(1, 3),
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			_ = cmd.Help()
@@ -70,17 +88,213 @@ You can do the following:
 			cache.Close()
 		case "show":
 			cache.Init(os.Getenv("POSH_SHELL"))
-			store := cache.Device
-			if session {
-				store = cache.Session
+			showCache(cacheStore())
+		case "get":
+			if len(args) < 2 {
+				fmt.Println("please provide a key")
+				exitcode = 2
+				return
+			}
+
+			cache.Init(os.Getenv("POSH_SHELL"))
+			getCacheValue(cacheStore(), args[1])
+		case "set":
+			if len(args) < 3 {
+				fmt.Println("please provide a key and a value")
+				exitcode = 2
+				return
+			}
+
+			duration := cache.Duration(cacheDuration)
+			if duration.Seconds() == 0 {
+				fmt.Println("invalid duration:", cacheDuration)
+				exitcode = 2
+				return
+			}
+
+			cache.Init(os.Getenv("POSH_SHELL"), cache.Persist)
+			cache.Set(cacheStore(), args[1], args[2], duration)
+			cache.Close()
+		case "delete":
+			if len(args) < 2 && keyPrefix == "" {
+				fmt.Println("please provide a key or a prefix")
+				exitcode = 2
+				return
 			}
 
-			fmt.Println(cache.Print(store))
+			cache.Init(os.Getenv("POSH_SHELL"), cache.Persist)
+			deleteCacheKeys(cacheStore(), args[1:])
+			cache.Close()
+		case "prune":
+			cache.Init(os.Getenv("POSH_SHELL"), cache.Persist)
+			pruneCache()
+			cache.Close()
 		}
 	},
 }
 
 func init() {
-	cacheCmd.Flags().BoolVarP(&session, "session", "s", false, "show the session cache")
+	cacheCmd.Flags().BoolVarP(&session, "session", "s", false, "use the session cache")
+	cacheCmd.Flags().StringVarP(&keyPrefix, "prefix", "p", "", "only use the keys starting with this prefix")
+	cacheCmd.Flags().BoolVar(&jsonOutput, "json", false, "print the output as JSON")
+	cacheCmd.Flags().StringVar(&cacheDuration, "duration", string(cache.INFINITE), "how long to keep the value when using set, for example 1h")
 	RootCmd.AddCommand(cacheCmd)
 }
+
+func cacheStore() cache.Store {
+	if session {
+		return cache.Session
+	}
+
+	return cache.Device
+}
+
+type cacheEntry struct {
+	Value   any                `json:"value"`
+	Expires *time.Time         `json:"expires,omitempty"`
+	Owner   *config.CacheOwner `json:"owner,omitempty"`
+	Key     string             `json:"key"`
+	Type    string             `json:"type"`
+	Created time.Time          `json:"created"`
+	Expired bool               `json:"expired"`
+}
+
+// cacheConfig resolves the config like init and print do: the --config flag,
+// or the config the current shell session was initialized with.
+func cacheConfig() (*config.Config, bool) {
+	if len(configFlag) != 0 {
+		return config.Load(configFlag, false), true
+	}
+
+	// without a session, Get falls back to the default config
+	if _, OK := cache.Get[string](cache.Session, config.SourceKey); !OK {
+		return nil, false
+	}
+
+	return config.Get("", false), true
+}
+
+func showCache(store cache.Store) {
+	cfg, hasConfig := cacheConfig()
+
+	cacheOwner := func(key string) (*config.CacheOwner, bool) {
+		if !hasConfig {
+			return nil, false
+		}
+
+		return cfg.CacheOwner(key)
+	}
+
+	if !jsonOutput {
+		owner := func(key string) string {
+			owner, OK := cacheOwner(key)
+			if !OK {
+				return ""
+			}
+
+			return fmt.Sprintf("%s (%s)", owner.Segment, owner.Strategy)
+		}
+
+		fmt.Println(cache.Print(store, keyPrefix, owner))
+		return
+	}
+
+	entries := cache.Entries(store, keyPrefix)
+
+	result := make([]*cacheEntry, 0, len(entries))
+	for key, entry := range entries {
+		item := &cacheEntry{
+			Key:     key,
+			Value:   entry.Value,
+			Type:    fmt.Sprintf("%T", entry.Value),
+			Created: time.Unix(entry.Timestamp, 0),
+			Expired: entry.Expired(),
+		}
+
+		if expires := entry.Expires(); !expires.IsZero() {
+			item.Expires = &expires
+		}
+
+		if owner, OK := cacheOwner(key); OK {
+			item.Owner = owner
+		}
+
+		result = append(result, item)
+	}
+
+	slices.SortFunc(result, func(a, b *cacheEntry) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	printJSON(result)
+}
+
+func getCacheValue(store cache.Store, key string) {
+	value, OK := cache.Get[any](store, key)
+	if !OK {
+		fmt.Printf("key %s not found\n", key)
+		exitcode = 1
+		return
+	}
+
+	if jsonOutput {
+		printJSON(value)
+		return
+	}
+
+	fmt.Println(value)
+}
+
+func deleteCacheKeys(store cache.Store, keys []string) {
+	if len(keys) == 0 {
+		for key := range cache.Entries(store, keyPrefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		cache.Delete(store, key)
+	}
+
+	fmt.Printf("deleted %d keys\n", len(keys))
+}
+
+// pruneCache removes the values stored by segments that are no longer in the config.
+func pruneCache() {
+	cfg, OK := cacheConfig()
+	if !OK {
+		fmt.Println("unable to resolve your config, use --config or run this from a shell initialized with oh-my-posh")
+		exitcode = 2
+		return
+	}
+
+	var count int
+
+	for _, store := range []cache.Store{cache.Session, cache.Device} {
+		for key := range cache.Entries(store, keyPrefix) {
+			if !config.IsSegmentCacheKey(key) {
+				continue
+			}
+
+			if _, OK := cfg.CacheOwner(key); OK {
+				continue
+			}
+
+			cache.Delete(store, key)
+			count++
+		}
+	}
+
+	fmt.Printf("pruned %d keys\n", count)
+}
+
+func printJSON(value any) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+		exitcode = 1
+		return
+	}
+
+	fmt.Println(string(data))
+}
@@ -53,6 +53,11 @@ Exports the current config to "~/new_config.omp.json" (in JSON format).`,
 
 		cfg := config.Load(configFlag, false)
 
+		// the config is exported as is, the errors are only reported
+		for _, err := range cfg.Errors {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
 		validateExportFormat := func() error {
 			format = strings.ToLower(format)
 			switch format {
@@ -71,6 +71,7 @@ Exports the config to an image file using customized output settings.`,
 		env.Init(flags)
 
 		template.Init(env, cfg.Var, cfg.Maps)
+		template.SetPartials(cfg.Templates)
 
 		defer func() {
 			template.SaveCache()
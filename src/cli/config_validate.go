@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/config"
+
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate your config",
+	Long: `Validate your config.
+
+Reports the errors reading your config, and the template references to properties that don't exist.
+
+Example usage:
+
+> oh-my-posh config validate --config ~/myconfig.omp.json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		cache.Init(os.Getenv("POSH_SHELL"))
+
+		err := setConfigFlag()
+		if err != nil {
+			exitcode = 666
+			fmt.Println(err.Error())
+			return
+		}
+
+		cfg := config.Load(configFlag, false)
+
+		if len(cfg.Errors) == 0 {
+			fmt.Println("config is valid")
+			return
+		}
+
+		for _, err := range cfg.Errors {
+			fmt.Println(err)
+		}
+
+		exitcode = 1
+	},
+}
+
+func init() {
+	configCmd.AddCommand(validateCmd)
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/config"
 	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/profile"
 	"github.com/jandedobbeleer/oh-my-posh/src/prompt"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/fixture"
 	"github.com/jandedobbeleer/oh-my-posh/src/shell"
 	"github.com/jandedobbeleer/oh-my-posh/src/template"
 	"github.com/jandedobbeleer/oh-my-posh/src/terminal"
@@ -20,8 +22,12 @@ import (
 
 // debugCmd represents the debug command
 var (
-	debugCmd  = createDebugCmd()
-	startTime = time.Now()
+	debugCmd   = createDebugCmd()
+	startTime  = time.Now()
+	recordPath string
+	unredacted bool
+	tracePath  string
+	profiling  bool
 )
 
 func init() {
@@ -45,14 +51,24 @@ func createDebugCmd() *cobra.Command {
 				Plain: plain,
 			}
 
-			env := &runtime.Terminal{}
-			env.Init(flags)
+			terminalEnv := &runtime.Terminal{}
+			terminalEnv.Init(flags)
+
+			var env runtime.Environment = terminalEnv
+
+			var recorder *fixture.Recorder
+			if len(recordPath) != 0 {
+				recorder = fixture.NewRecorder(terminalEnv, unredacted)
+				env = recorder
+			}
 
 			cache.Init(os.Getenv("POSH_SHELL"))
 
 			cfg := getDebugConfig(configFlag)
 
 			template.Init(env, cfg.Var, cfg.Maps)
+			template.SetPartials(cfg.Templates)
+			template.SetPrecompiled(cfg.CompiledTemplates)
 
 			defer func() {
 				template.SaveCache()
@@ -70,11 +86,32 @@ func createDebugCmd() *cobra.Command {
 				Plain:  plain,
 			}
 
+			if profiling || len(tracePath) != 0 {
+				eng.Profile = profile.New()
+			}
+
 			fmt.Print(eng.PrintDebug(startTime, build.Version))
+
+			if len(tracePath) != 0 {
+				writeTrace(eng.Profile)
+			}
+
+			if recorder == nil {
+				return
+			}
+
+			if err := recorder.Save(recordPath); err != nil {
+				fmt.Println("unable to save the recorded environment:", err)
+				exitcode = 1
+			}
 		},
 	}
 
 	debugCmd.Flags().StringVar(&pwd, "pwd", "", "current working directory")
+	debugCmd.Flags().StringVar(&recordPath, "record", "", "record the environment of this render to a fixture file")
+	debugCmd.Flags().BoolVar(&unredacted, "unredacted", false, "record credentials and HTTP responses as is")
+	debugCmd.Flags().BoolVar(&profiling, "profile", false, "break down the time spent by every segment")
+	debugCmd.Flags().StringVar(&tracePath, "trace", "", "write the profile to a file in the Chrome trace event format")
 
 	// Deprecated flags, should be kept to avoid breaking CLI integration.
 	debugCmd.Flags().StringVar(&shellName, "shell", "", "the shell to print for")
@@ -85,6 +122,18 @@ func createDebugCmd() *cobra.Command {
 	return debugCmd
 }
 
+func writeTrace(p *profile.Profile) {
+	data, err := p.ChromeTrace()
+	if err == nil {
+		err = os.WriteFile(tracePath, data, 0o644)
+	}
+
+	if err != nil {
+		fmt.Println("unable to write the trace:", err)
+		exitcode = 1
+	}
+}
+
 func getDebugConfig(configpath string) *config.Config {
 	if len(configpath) != 0 {
 		return config.Load(configpath, false)
@@ -2,12 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/prompt"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
 	"github.com/jandedobbeleer/oh-my-posh/src/shell"
 	"github.com/jandedobbeleer/oh-my-posh/src/template"
+	"github.com/jandedobbeleer/oh-my-posh/src/terminal"
 
 	"github.com/spf13/cobra"
 )
@@ -31,6 +34,7 @@ var (
 	noStatus     bool
 	column       int
 	escape       bool
+	target       string
 )
 
 // printCmd represents the print command
@@ -42,9 +46,15 @@ func init() {
 
 func createPrintCmd() *cobra.Command {
 	printCmd := &cobra.Command{
-		Use:   "print [debug|primary|secondary|transient|right|tooltip|valid|error|preview]",
+		Use:   "print [debug|primary|secondary|transient|right|tooltip|valid|error|preview|statusline|pwd]",
 		Short: "Print the prompt/context",
-		Long:  "Print one of the prompts based on the location/use-case.",
+		Long: `Print one of the prompts based on the location/use-case.
+
+The statusline prompt renders the status_line block for a terminal multiplexer or emulator,
+using the format of the --target (tmux, zellij or wezterm).
+
+The pwd prompt renders the current working directory sequence set in pwd,
+for the shells which draw the prompt themselves.`,
 		ValidArgs: []string{
 			prompt.DEBUG,
 			prompt.PRIMARY,
@@ -55,6 +65,8 @@ func createPrintCmd() *cobra.Command {
 			prompt.VALID,
 			prompt.ERROR,
 			prompt.PREVIEW,
+			prompt.STATUSLINE,
+			prompt.PWD,
 		},
 		Args: NoArgsOrOneValidArg,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -63,10 +75,17 @@ func createPrintCmd() *cobra.Command {
 				return
 			}
 
-			if shellName == "" {
+			// status lines are never interpreted by a shell
+			if shellName == "" || args[0] == prompt.STATUSLINE {
 				shellName = shell.GENERIC
 			}
 
+			if args[0] == prompt.STATUSLINE && !slices.Contains(terminal.StatusLineTargets, target) {
+				fmt.Printf("invalid target: %s, must be one of %s\n", target, strings.Join(terminal.StatusLineTargets, ", "))
+				exitcode = 2
+				return
+			}
+
 			flags := &runtime.Flags{
 				ConfigPath:    configFlag,
 				PWD:           pwd,
@@ -123,6 +142,10 @@ func createPrintCmd() *cobra.Command {
 				fmt.Print(eng.ExtraPrompt(prompt.Error))
 			case prompt.PREVIEW:
 				fmt.Print(eng.Preview())
+			case prompt.STATUSLINE:
+				fmt.Print(eng.StatusLine(target))
+			case prompt.PWD:
+				fmt.Print(eng.Pwd())
 			default:
 				_ = cmd.Help()
 			}
@@ -147,6 +170,7 @@ func createPrintCmd() *cobra.Command {
 	printCmd.Flags().BoolVar(&saveCache, "save-cache", false, "save updated cache to file")
 	printCmd.Flags().BoolVar(&escape, "escape", true, "escape the ANSI sequences for the shell")
 	printCmd.Flags().BoolVarP(&force, "force", "f", false, "force rendering the segments")
+	printCmd.Flags().StringVar(&target, "target", terminal.Tmux, "the status line target: tmux, zellij or wezterm")
 
 	// Hide flags that are for internal use only.
 	_ = printCmd.Flags().MarkHidden("save-cache")
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/dsc"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
@@ -12,14 +13,19 @@ import (
 
 // shellCmd represents the shell command
 var shellCmd = &cobra.Command{
-	Use:   "shell get",
-	Short: "Get the shell name",
-	Long: `Get the shell name.
+	Use:   "shell [get|features]",
+	Short: "Get the shell name or supported features",
+	Long: `Get the shell name or supported features.
 
-This command retrieves the name of the current shell being used.`,
-	Example: `  oh-my-posh shell get`,
+You can do the following:
+
+- get: retrieve the name of the current shell being used
+- features: list which features are supported in which shell`,
+	Example: `  oh-my-posh shell get
+  oh-my-posh shell features`,
 	ValidArgs: []string{
 		"get",
+		"features",
 	},
 	Args: NoArgsOrOneValidArg,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -38,12 +44,72 @@ This command retrieves the name of the current shell being used.`,
 		switch args[0] {
 		case "get":
 			fmt.Print(env.Shell())
+		case "features":
+			fmt.Print(featureMatrix())
 		default:
 			_ = cmd.Help()
 		}
 	},
 }
 
+func featureMatrix() string {
+	var builder strings.Builder
+
+	nameLength := 0
+	for _, support := range shell.Matrix {
+		nameLength = max(nameLength, len(support.Name))
+	}
+
+	fmt.Fprintf(&builder, "%-*s", nameLength, "")
+	for _, sh := range shell.MatrixShells {
+		fmt.Fprintf(&builder, "  %-6s", sh)
+	}
+
+	builder.WriteString("\n")
+
+	var notes []string
+
+	for _, support := range shell.Matrix {
+		fmt.Fprintf(&builder, "%-*s", nameLength, support.Name)
+
+		for _, sh := range shell.MatrixShells {
+			mark := "-"
+			if support.Supports(sh) {
+				mark = "✓"
+			}
+
+			if note, ok := support.Notes[sh]; ok {
+				notes = append(notes, fmt.Sprintf("[%d] %s in %s: %s", len(notes)+1, support.Name, sh, note))
+				mark += fmt.Sprintf(" [%d]", len(notes))
+			}
+
+			fmt.Fprintf(&builder, "  %-6s", mark)
+		}
+
+		builder.WriteString("\n")
+	}
+
+	if len(notes) != 0 {
+		builder.WriteString("\n")
+		builder.WriteString(strings.Join(notes, "\n"))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\nEnable a feature using its setting in the config:\n\n")
+
+	for _, support := range shell.Matrix {
+		fmt.Fprintf(&builder, "%-*s  %s\n", nameLength, support.Name, support.Setting)
+	}
+
+	// the columns are padded, which leaves trailing spaces
+	lines := strings.Split(builder.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func init() {
 	shellCmd.AddCommand(dsc.Command(shell.DSC()))
 	RootCmd.AddCommand(shellCmd)
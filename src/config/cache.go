@@ -1,10 +1,20 @@
 package config
 
-import "github.com/jandedobbeleer/oh-my-posh/src/cache"
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+)
 
 type Cache struct {
-	Duration cache.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty"`
-	Strategy Strategy       `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty"`
+	Duration   cache.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty"`
+	Strategy   Strategy       `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty"`
+	WatchFiles []string       `json:"watch_files,omitempty" toml:"watch_files,omitempty" yaml:"watch_files,omitempty"`
 }
 
 type Strategy string
@@ -12,4 +22,161 @@ type Strategy string
 const (
 	Folder  Strategy = "folder"
 	Session Strategy = "session"
+	Device  Strategy = "device"
+
+	// LastKnown isn't a strategy you can configure, it's used for
+	// the values we keep to render segments that exceed the latency budget.
+	LastKnown Strategy = "last_known"
+
+	segmentCacheKey       = "segment_cache_"
+	segmentDeviceCacheKey = "segment_device_cache_"
+	segmentLastKnownKey   = "segment_last_known_"
 )
+
+// cachedSegment is what we store for a cached segment. The fingerprint
+// of the watch files tells us whether the value is still valid.
+type cachedSegment struct {
+	Fingerprint string          `json:"fingerprint,omitempty"`
+	Writer      json.RawMessage `json:"writer"`
+}
+
+// lastKnownSegment is what we store to render a segment that exceeds the latency budget.
+// There's one per segment, the value is only used in the folder it was stored for.
+type lastKnownSegment struct {
+	Pwd    string          `json:"pwd"`
+	Writer json.RawMessage `json:"writer"`
+}
+
+// fingerprint hashes the content of the watch files.
+func (c *Cache) fingerprint(env runtime.Environment) string {
+	if len(c.WatchFiles) == 0 {
+		return ""
+	}
+
+	h := fnv.New64a()
+
+	for _, file := range c.WatchFiles {
+		path, OK := resolveWatchFile(env, file)
+		if !OK {
+			fmt.Fprintf(h, "%s:missing;", file)
+			continue
+		}
+
+		fmt.Fprintf(h, "%s:%s;", path, env.FileContent(path))
+	}
+
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// watchPaths returns the paths of the watch files, a missing relative
+// file is expected in the current folder so creating it is noticed.
+func (c *Cache) watchPaths(env runtime.Environment) []string {
+	if c == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(c.WatchFiles))
+
+	for _, file := range c.WatchFiles {
+		path, OK := resolveWatchFile(env, file)
+		if !OK {
+			path = filepath.Join(env.Pwd(), file)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// resolveWatchFile returns the path of a watch file. Relative paths are looked up
+// from the current folder upwards, like the language segments find their files.
+func resolveWatchFile(env runtime.Environment, file string) (string, bool) {
+	switch {
+	case strings.HasPrefix(file, "~"):
+		return filepath.Join(env.Home(), file[1:]), true
+	case filepath.IsAbs(file):
+		return file, true
+	}
+
+	info, err := env.HasParentFilePath(file, false)
+	if err != nil {
+		return "", false
+	}
+
+	return info.Path, true
+}
+
+// CacheOwner is the segment that stored a cache entry, and the strategy it used.
+type CacheOwner struct {
+	Segment  string   `json:"segment"`
+	Strategy Strategy `json:"strategy"`
+}
+
+// IsSegmentCacheKey tells whether a cache entry was stored by a segment.
+func IsSegmentCacheKey(key string) bool {
+	for _, prefix := range []string{segmentCacheKey, segmentDeviceCacheKey, segmentLastKnownKey} {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CacheOwner returns the segment in the config that stored the cache entry.
+func (cfg *Config) CacheOwner(key string) (*CacheOwner, bool) {
+	if !IsSegmentCacheKey(key) {
+		return nil, false
+	}
+
+	var owner *CacheOwner
+
+	match := func(segment *Segment) {
+		if owner != nil {
+			return
+		}
+
+		name := segment.Name()
+
+		switch {
+		case key == segmentCacheKey+name:
+			owner = &CacheOwner{Segment: name, Strategy: Session}
+		case isFolderKey(key, segmentCacheKey+name):
+			owner = &CacheOwner{Segment: name, Strategy: Folder}
+		case isFolderKey(key, segmentDeviceCacheKey+name):
+			owner = &CacheOwner{Segment: name, Strategy: Device}
+		case key == segmentLastKnownKey+name:
+			owner = &CacheOwner{Segment: name, Strategy: LastKnown}
+		}
+	}
+
+	for _, block := range cfg.Blocks {
+		for _, segment := range block.Segments {
+			match(segment)
+		}
+	}
+
+	for _, tooltip := range cfg.Tooltips {
+		match(tooltip)
+	}
+
+	if cfg.StatusLine != nil {
+		for _, segment := range cfg.StatusLine.Segments {
+			match(segment)
+		}
+	}
+
+	return owner, owner != nil
+}
+
+// isFolderKey tells whether the key is the prefix followed by the folder the value was stored for.
+// The folder is always an absolute path, so a segment named Git doesn't claim the keys of Git_Work.
+func isFolderKey(key, prefix string) bool {
+	folder, found := strings.CutPrefix(key, prefix+"_")
+	if !found {
+		return false
+	}
+
+	return strings.HasPrefix(folder, "/") || strings.HasPrefix(folder, `\`) || len(filepath.VolumeName(folder)) != 0
+}
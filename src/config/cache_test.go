@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheOwner(t *testing.T) {
+	cfg := &Config{
+		Blocks: []*Block{
+			{
+				Segments: []*Segment{
+					{Type: GIT},
+					{Type: GIT, Alias: "Git_Work"},
+					{Type: NODE},
+				},
+			},
+		},
+		Tooltips: []*Segment{
+			{Type: AWS},
+		},
+	}
+
+	cases := []struct {
+		Expected *CacheOwner
+		Case     string
+		Key      string
+	}{
+		{Case: "session strategy", Key: "segment_cache_Node", Expected: &CacheOwner{Segment: "Node", Strategy: Session}},
+		{Case: "folder strategy", Key: "segment_cache_Git_/home/user/repo", Expected: &CacheOwner{Segment: "Git", Strategy: Folder}},
+		{Case: "exact name", Key: "segment_cache_Git_Work_/home/user/repo", Expected: &CacheOwner{Segment: "Git_Work", Strategy: Folder}},
+		{Case: "removed segment sharing a prefix", Key: "segment_cache_Git_Personal_/home/user/repo"},
+		{Case: "removed segment sharing a prefix, session strategy", Key: "segment_cache_Git_Personal"},
+		{Case: "device strategy", Key: "segment_device_cache_Node_/home/user/repo", Expected: &CacheOwner{Segment: "Node", Strategy: Device}},
+		{Case: "last known value", Key: "segment_last_known_Aws", Expected: &CacheOwner{Segment: "Aws", Strategy: LastKnown}},
+		{Case: "segment not in config", Key: "segment_cache_Python_/home/user"},
+		{Case: "not a segment key", Key: "upgrade_check"},
+	}
+
+	for _, tc := range cases {
+		owner, OK := cfg.CacheOwner(tc.Key)
+		assert.Equal(t, tc.Expected != nil, OK, tc.Case)
+		assert.Equal(t, tc.Expected, owner, tc.Case)
+	}
+}
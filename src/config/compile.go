@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/template"
+)
+
+// compileTemplates patches every template in the config once and validates the
+// field references against the segment they belong to, including the partials they use.
+// The patched templates are kept in the config so they are stored in the config cache as well.
+func (cfg *Config) compileTemplates() []error {
+	defer log.Trace(time.Now())
+
+	// the user defined partials need to be known before we can compile the templates using them
+	errors := template.SetPartials(cfg.Templates)
+	for _, err := range errors {
+		log.Error(err)
+	}
+
+	compile := func(owner string, context any, templates ...string) {
+		for _, tmpl := range templates {
+			if _, err := template.Compile(tmpl, context); err != nil {
+				err = fmt.Errorf("%s: %w", owner, err)
+				log.Error(err)
+				errors = append(errors, err)
+			}
+		}
+	}
+
+	compileSegment := func(segment *Segment) {
+		f, ok := Segments[segment.Type]
+		if !ok {
+			return
+		}
+
+		templates := []string{segment.Template, string(segment.Style)}
+		templates = append(templates, segment.Templates...)
+		templates = append(templates, segment.ForegroundTemplates...)
+		templates = append(templates, segment.BackgroundTemplates...)
+
+		compile(segment.Name(), f(), templates...)
+	}
+
+	for _, block := range cfg.Blocks {
+		for _, segment := range block.Segments {
+			compileSegment(segment)
+		}
+	}
+
+	for _, tooltip := range cfg.Tooltips {
+		compileSegment(tooltip)
+	}
+
+	if cfg.StatusLine != nil {
+		for _, segment := range cfg.StatusLine.Segments {
+			compileSegment(segment)
+		}
+	}
+
+	extraPrompts := []struct {
+		prompt *Segment
+		name   string
+	}{
+		{name: "debug_prompt", prompt: cfg.DebugPrompt},
+		{name: "secondary_prompt", prompt: cfg.SecondaryPrompt},
+		{name: "transient_prompt", prompt: cfg.TransientPrompt},
+		{name: "valid_line", prompt: cfg.ValidLine},
+		{name: "error_line", prompt: cfg.ErrorLine},
+	}
+
+	for _, extra := range extraPrompts {
+		prompt := extra.prompt
+		if prompt == nil {
+			continue
+		}
+
+		templates := []string{prompt.Template}
+		templates = append(templates, prompt.ForegroundTemplates...)
+		templates = append(templates, prompt.BackgroundTemplates...)
+
+		compile(extra.name, nil, templates...)
+	}
+
+	compile("console_title_template", nil, cfg.ConsoleTitleTemplate)
+	compile("pwd", nil, cfg.PWD)
+
+	cfg.CompiledTemplates = template.Precompiled()
+
+	return errors
+}
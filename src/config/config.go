@@ -72,8 +72,13 @@ type Config struct {
 	Cycle                   color.Cycle            `json:"cycle,omitempty" toml:"cycle,omitempty" yaml:"cycle,omitempty"`
 	ITermFeatures           terminal.ITermFeatures `json:"iterm_features,omitempty" toml:"iterm_features,omitempty" yaml:"iterm_features,omitempty"`
 	Tooltips                []*Segment             `json:"tooltips,omitempty" toml:"tooltips,omitempty" yaml:"tooltips,omitempty"`
+	StatusLine              *Block                 `json:"status_line,omitempty" toml:"status_line,omitempty" yaml:"status_line,omitempty"`
+	Templates               map[string]string      `json:"templates,omitempty" toml:"templates,omitempty" yaml:"templates,omitempty"`
+	CompiledTemplates       maps.Simple[string]    `json:"-" toml:"-" yaml:"-"`
+	Errors                  []string               `json:"-" toml:"-" yaml:"-"`
 	hash                    uint64
 	Version                 int  `json:"version" toml:"version" yaml:"version"`
+	LatencyBudget           int  `json:"latency_budget,omitempty" toml:"latency_budget,omitempty" yaml:"latency_budget,omitempty"`
 	MigrateGlyphs           bool `json:"-" toml:"-" yaml:"-"`
 	Async                   bool `json:"async,omitempty" toml:"async,omitempty" yaml:"async,omitempty"`
 	ShellIntegration        bool `json:"shell_integration,omitempty" toml:"shell_integration,omitempty" yaml:"shell_integration,omitempty"`
@@ -88,7 +93,15 @@ type Config struct {
 
 func (cfg *Config) MakeColors(env runtime.Environment) color.String {
 	cacheDisabled := env.Getenv("OMP_CACHE_DISABLED") == "1"
-	return color.MakeColors(cfg.getPalette(), !cacheDisabled, cfg.AccentColor, env)
+	palette := cfg.getPalette()
+
+	// allow the template color functions to use palette references
+	template.ResolveColor = func(name string) (string, error) {
+		resolved, err := palette.ResolveColor(color.Ansi(name))
+		return resolved.String(), err
+	}
+
+	return color.MakeColors(palette, !cacheDisabled, cfg.AccentColor, env)
 }
 
 func (cfg *Config) getPalette() color.Palette {
@@ -132,16 +145,16 @@ func (cfg *Config) Features(env runtime.Environment) shell.Features {
 		feats |= shell.Transient
 	}
 
-	unsupportedShells := []string{shell.ELVISH, shell.XONSH}
-	if slices.Contains(unsupportedShells, env.Shell()) {
-		cfg.ShellIntegration = false
-	}
-
 	if cfg.ShellIntegration {
 		log.Debug("shell integration enabled")
 		feats |= shell.FTCSMarks
 	}
 
+	if len(cfg.PWD) != 0 {
+		log.Debug("pwd enabled")
+		feats |= shell.PWD
+	}
+
 	// do not enable upgrade features when async is enabled
 	if feats&shell.Async == 0 {
 		feats |= cfg.upgradeFeatures()
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
@@ -183,3 +185,59 @@ func TestUpgradeFeatures(t *testing.T) {
 		cache.DeleteAll(cache.Device)
 	}
 }
+
+func TestCompileTemplates(t *testing.T) {
+	cfg := &Config{
+		Blocks: []*Block{
+			{
+				Segments: []*Segment{
+					{Type: TEXT, Template: "{{ .Text }} {{ .Shell }}"},
+					{Type: GIT, Alias: "Repo", Template: "{{ .HEAD }} {{ .Unknown }}"},
+				},
+			},
+		},
+		TransientPrompt: &Segment{Template: "{{ .Folder }} {{ .Nope }}"},
+	}
+
+	errors := cfg.compileTemplates()
+
+	assert.Len(t, errors, 2)
+	assert.ErrorContains(t, errors[0], "Repo: unknown field .Unknown")
+	assert.ErrorContains(t, errors[1], "transient_prompt: unknown field .Nope")
+	assert.Equal(t, "{{.Data.Text}} {{.Shell}}", cfg.CompiledTemplates["segments.Text|{{ .Text }} {{ .Shell }}"])
+}
+
+func TestCompileTemplatesWithPartials(t *testing.T) {
+	cfg := &Config{
+		Templates: map[string]string{
+			"text": "{{ .Text }}",
+			"loop": `{{ template "loop" . }}`,
+		},
+		Blocks: []*Block{
+			{
+				Segments: []*Segment{
+					{Type: TEXT, Template: `{{ template "text" . }}`},
+				},
+			},
+		},
+	}
+
+	errors := cfg.compileTemplates()
+
+	assert.Len(t, errors, 1)
+	assert.ErrorContains(t, errors[0], "template loop: recursive reference")
+	assert.Equal(t, `{{define "text"}}{{.Data.Text}}{{end}}{{template "text" .}}`, cfg.CompiledTemplates[`segments.Text|{{ template "text" . }}`])
+
+	template.SetPartials(nil)
+}
+
+func TestLoadErrors(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "posh.omp.json")
+	content := `{"version": 3, "blocks": [{"type": "prompt", "segments": [{"type": "git", "alias": "Repo", "template": "{{ .HEAD }} {{ .Unknown }}"}]}]}`
+	assert.NoError(t, os.WriteFile(configFile, []byte(content), 0o644))
+
+	cfg := Load(configFile, false)
+
+	assert.Len(t, cfg.Errors, 1)
+	assert.Contains(t, cfg.Errors[0], "Repo: unknown field .Unknown")
+}
@@ -40,6 +40,10 @@ func Load(configFile string, migrate bool) *Config {
 
 	cfg.toggleSegments()
 
+	for _, err := range cfg.compileTemplates() {
+		cfg.Errors = append(cfg.Errors, err.Error())
+	}
+
 	// only migrate automatically when the switch isn't set
 	if !migrate && cfg.Version < Version {
 		cfg.BackupAndMigrate()
@@ -114,7 +118,9 @@ func parseConfigFile(configFile string) *Config {
 	cfg, err := readConfig(configFile, h)
 	if err != nil {
 		log.Error(err)
-		return Default(true)
+		cfg = Default(true)
+		cfg.Errors = append(cfg.Errors, err.Error())
+		return cfg
 	}
 
 	parentFolder := filepath.Dir(configFile)
@@ -124,6 +130,7 @@ func parseConfigFile(configFile string) *Config {
 		base, err := readConfig(cfg.Extends, h)
 		if err != nil {
 			log.Error(err)
+			cfg.Errors = append(cfg.Errors, err.Error())
 			break
 		}
 
@@ -132,6 +139,7 @@ func parseConfigFile(configFile string) *Config {
 		err = base.merge(cfg)
 		if err != nil {
 			log.Error(err)
+			cfg.Errors = append(cfg.Errors, err.Error())
 			break
 		}
 
@@ -1,8 +1,8 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"slices"
 	"strings"
 	"time"
@@ -10,6 +10,7 @@ import (
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/color"
 	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/profile"
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
 	"github.com/jandedobbeleer/oh-my-posh/src/regex"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
@@ -36,42 +37,47 @@ func (s *SegmentStyle) resolve(context any) SegmentStyle {
 type Segment struct {
 	writer                 SegmentWriter
 	env                    runtime.Environment
+	abandon                context.CancelFunc
+	abandoned              bool
 	Properties             properties.Map `json:"properties,omitempty" toml:"properties,omitempty" yaml:"properties,omitempty"`
 	Cache                  *Cache         `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty"`
 	Alias                  string         `json:"alias,omitempty" toml:"alias,omitempty" yaml:"alias,omitempty"`
 	styleCache             SegmentStyle
 	name                   string
-	LeadingDiamond         string         `json:"leading_diamond,omitempty" toml:"leading_diamond,omitempty" yaml:"leading_diamond,omitempty"`
-	TrailingDiamond        string         `json:"trailing_diamond,omitempty" toml:"trailing_diamond,omitempty" yaml:"trailing_diamond,omitempty"`
-	Template               string         `json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty"`
-	Foreground             color.Ansi     `json:"foreground,omitempty" toml:"foreground,omitempty" yaml:"foreground,omitempty"`
-	TemplatesLogic         template.Logic `json:"templates_logic,omitempty" toml:"templates_logic,omitempty" yaml:"templates_logic,omitempty"`
-	PowerlineSymbol        string         `json:"powerline_symbol,omitempty" toml:"powerline_symbol,omitempty" yaml:"powerline_symbol,omitempty"`
-	Background             color.Ansi     `json:"background,omitempty" toml:"background,omitempty" yaml:"background,omitempty"`
-	Filler                 string         `json:"filler,omitempty" toml:"filler,omitempty" yaml:"filler,omitempty"`
-	Type                   SegmentType    `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty"`
-	Style                  SegmentStyle   `json:"style,omitempty" toml:"style,omitempty" yaml:"style,omitempty"`
-	LeadingPowerlineSymbol string         `json:"leading_powerline_symbol,omitempty" toml:"leading_powerline_symbol,omitempty" yaml:"leading_powerline_symbol,omitempty"`
-	ForegroundTemplates    template.List  `json:"foreground_templates,omitempty" toml:"foreground_templates,omitempty" yaml:"foreground_templates,omitempty"`
-	Tips                   []string       `json:"tips,omitempty" toml:"tips,omitempty" yaml:"tips,omitempty"`
-	BackgroundTemplates    template.List  `json:"background_templates,omitempty" toml:"background_templates,omitempty" yaml:"background_templates,omitempty"`
-	Templates              template.List  `json:"templates,omitempty" toml:"templates,omitempty" yaml:"templates,omitempty"`
-	ExcludeFolders         []string       `json:"exclude_folders,omitempty" toml:"exclude_folders,omitempty" yaml:"exclude_folders,omitempty"`
-	IncludeFolders         []string       `json:"include_folders,omitempty" toml:"include_folders,omitempty" yaml:"include_folders,omitempty"`
-	Needs                  []string       `json:"-" toml:"-" yaml:"-"`
-	Timeout                time.Duration  `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
-	MaxWidth               int            `json:"max_width,omitempty" toml:"max_width,omitempty" yaml:"max_width,omitempty"`
-	MinWidth               int            `json:"min_width,omitempty" toml:"min_width,omitempty" yaml:"min_width,omitempty"`
-	Duration               time.Duration  `json:"-" toml:"-" yaml:"-"`
-	NameLength             int            `json:"-" toml:"-" yaml:"-"`
-	Index                  int            `json:"index,omitempty" toml:"index,omitempty" yaml:"index,omitempty"`
-	Interactive            bool           `json:"interactive,omitempty" toml:"interactive,omitempty" yaml:"interactive,omitempty"`
-	Enabled                bool           `json:"-" toml:"-" yaml:"-"`
-	Newline                bool           `json:"newline,omitempty" toml:"newline,omitempty" yaml:"newline,omitempty"`
-	InvertPowerline        bool           `json:"invert_powerline,omitempty" toml:"invert_powerline,omitempty" yaml:"invert_powerline,omitempty"`
-	Force                  bool           `json:"force,omitempty" toml:"force,omitempty" yaml:"force,omitempty"`
-	restored               bool           `json:"-" toml:"-" yaml:"-"`
-	Toggled                bool           `json:"toggled,omitempty" toml:"toggled,omitempty" yaml:"toggled,omitempty"`
+	LeadingDiamond         string           `json:"leading_diamond,omitempty" toml:"leading_diamond,omitempty" yaml:"leading_diamond,omitempty"`
+	TrailingDiamond        string           `json:"trailing_diamond,omitempty" toml:"trailing_diamond,omitempty" yaml:"trailing_diamond,omitempty"`
+	Template               string           `json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty"`
+	Foreground             color.Ansi       `json:"foreground,omitempty" toml:"foreground,omitempty" yaml:"foreground,omitempty"`
+	TemplatesLogic         template.Logic   `json:"templates_logic,omitempty" toml:"templates_logic,omitempty" yaml:"templates_logic,omitempty"`
+	PowerlineSymbol        string           `json:"powerline_symbol,omitempty" toml:"powerline_symbol,omitempty" yaml:"powerline_symbol,omitempty"`
+	Background             color.Ansi       `json:"background,omitempty" toml:"background,omitempty" yaml:"background,omitempty"`
+	Filler                 string           `json:"filler,omitempty" toml:"filler,omitempty" yaml:"filler,omitempty"`
+	Type                   SegmentType      `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty"`
+	Style                  SegmentStyle     `json:"style,omitempty" toml:"style,omitempty" yaml:"style,omitempty"`
+	LeadingPowerlineSymbol string           `json:"leading_powerline_symbol,omitempty" toml:"leading_powerline_symbol,omitempty" yaml:"leading_powerline_symbol,omitempty"`
+	ForegroundTemplates    template.List    `json:"foreground_templates,omitempty" toml:"foreground_templates,omitempty" yaml:"foreground_templates,omitempty"`
+	Tips                   []string         `json:"tips,omitempty" toml:"tips,omitempty" yaml:"tips,omitempty"`
+	BackgroundTemplates    template.List    `json:"background_templates,omitempty" toml:"background_templates,omitempty" yaml:"background_templates,omitempty"`
+	Templates              template.List    `json:"templates,omitempty" toml:"templates,omitempty" yaml:"templates,omitempty"`
+	ExcludeFolders         []string         `json:"exclude_folders,omitempty" toml:"exclude_folders,omitempty" yaml:"exclude_folders,omitempty"`
+	IncludeFolders         []string         `json:"include_folders,omitempty" toml:"include_folders,omitempty" yaml:"include_folders,omitempty"`
+	Needs                  []string         `json:"-" toml:"-" yaml:"-"`
+	Timeout                time.Duration    `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxWidth               int              `json:"max_width,omitempty" toml:"max_width,omitempty" yaml:"max_width,omitempty"`
+	MinWidth               int              `json:"min_width,omitempty" toml:"min_width,omitempty" yaml:"min_width,omitempty"`
+	Duration               time.Duration    `json:"-" toml:"-" yaml:"-"`
+	Deadline               time.Time        `json:"-" toml:"-" yaml:"-"`
+	Tracker                *profile.Tracker `json:"-" toml:"-" yaml:"-"`
+	NameLength             int              `json:"-" toml:"-" yaml:"-"`
+	Index                  int              `json:"index,omitempty" toml:"index,omitempty" yaml:"index,omitempty"`
+	Interactive            bool             `json:"interactive,omitempty" toml:"interactive,omitempty" yaml:"interactive,omitempty"`
+	Enabled                bool             `json:"-" toml:"-" yaml:"-"`
+	Newline                bool             `json:"newline,omitempty" toml:"newline,omitempty" yaml:"newline,omitempty"`
+	InvertPowerline        bool             `json:"invert_powerline,omitempty" toml:"invert_powerline,omitempty" yaml:"invert_powerline,omitempty"`
+	Force                  bool             `json:"force,omitempty" toml:"force,omitempty" yaml:"force,omitempty"`
+	restored               bool             `json:"-" toml:"-" yaml:"-"`
+	Overrun                bool             `json:"-" toml:"-" yaml:"-"`
+	Toggled                bool             `json:"toggled,omitempty" toml:"toggled,omitempty" yaml:"toggled,omitempty"`
 }
 
 func (segment *Segment) Name() string {
@@ -101,7 +107,29 @@ func (segment *Segment) Execute(env runtime.Environment) {
 
 	defer segment.evaluateNeeds()
 
-	err := segment.MapSegmentWithWriter(env)
+	writerEnv := env
+
+	// the commands of a writer we stop waiting for are killed
+	if _, limited := segment.timeout(); limited {
+		var release context.CancelFunc
+		writerEnv, segment.abandon, release = runtime.WithCancel(env)
+
+		// an abandoned writer can still start commands, those need to be killed
+		defer func() {
+			if !segment.abandoned {
+				release()
+			}
+		}()
+	}
+
+	// the commands are kept across renders until a watch file changes
+	if watchFiles := segment.Cache.watchPaths(env); len(watchFiles) != 0 {
+		writerEnv = runtime.WithWatchFiles(watchFiles, writerEnv)
+	}
+
+	writerEnv = profile.WithTracker(segment.Tracker, writerEnv)
+
+	err := segment.MapSegmentWithWriter(writerEnv)
 	if err != nil || !segment.shouldIncludeFolder() {
 		return
 	}
@@ -120,27 +148,57 @@ func (segment *Segment) Execute(env runtime.Environment) {
 		return
 	}
 
-	if segment.Timeout == 0 {
+	timeout, limited := segment.timeout()
+
+	switch {
+	case !limited:
 		segment.Enabled = segment.writer.Enabled()
-	} else {
-		done := make(chan bool)
+	case timeout <= 0:
+		log.Debugf("no time left in the latency budget for segment: %s", segment.Name())
+		segment.restoreLastKnown(env)
+		return
+	default:
+		// the writer can't be stopped, so make sure it can always
+		// deliver its result and only touches its own state
+		done := make(chan bool, 1)
+		writer := segment.writer
 		go func() {
-			segment.Enabled = segment.writer.Enabled()
-			done <- true
+			done <- writer.Enabled()
 		}()
 
 		select {
-		case <-done:
-			// Completed before timeout
-		case <-time.After(segment.Timeout * time.Millisecond):
-			log.Debugf("timeout after %dms for segment: %s", segment.Timeout, segment.Name())
+		case enabled := <-done:
+			segment.Enabled = enabled
+		case <-time.After(timeout):
+			log.Debugf("timeout after %s for segment: %s", timeout, segment.Name())
+			segment.abandon()
+			segment.abandoned = true
+			segment.restoreLastKnown(env)
 			return
 		}
 	}
 
 	if segment.Enabled {
 		template.Cache.AddSegmentData(segment.Name(), segment.writer)
+		segment.setLastKnown()
+	}
+}
+
+// timeout returns how long the segment's writer is allowed to take, which is
+// the segment's timeout or what's left of the latency budget, whichever comes first.
+func (segment *Segment) timeout() (time.Duration, bool) {
+	timeout := segment.Timeout * time.Millisecond
+
+	if segment.Deadline.IsZero() {
+		return timeout, timeout != 0
+	}
+
+	remaining := time.Until(segment.Deadline)
+	if timeout != 0 && timeout < remaining {
+		return timeout, true
 	}
+
+	return remaining, true
 }
 
 func (segment *Segment) Render(index int, force bool) bool {
@@ -154,7 +212,9 @@ func (segment *Segment) Render(index int, force bool) bool {
 
 	segment.writer.SetIndex(index)
 
+	measured := segment.Tracker.Measure(profile.Template, segment.Name())
 	text := segment.string()
+	measured()
 	segment.Enabled = segment.Force || len(strings.ReplaceAll(text, " ", "")) > 0
 
 	if !segment.Enabled {
@@ -250,13 +310,24 @@ func (segment *Segment) restoreCache() bool {
 	}
 
 	cacheKey := segment.cacheKey()
-	data, OK := cache.Get[string](cache.Session, cacheKey)
+	data, OK := cache.Get[string](segment.cacheStore(), cacheKey)
 	if !OK {
 		log.Debugf("no cache found for segment: %s, key: %s", segment.Name(), cacheKey)
 		return false
 	}
 
-	err := json.Unmarshal([]byte(data), &segment.writer)
+	var cached cachedSegment
+	if err := json.Unmarshal([]byte(data), &cached); err != nil || len(cached.Writer) == 0 {
+		log.Debugf("invalid cache for segment: %s, key: %s", segment.Name(), cacheKey)
+		return false
+	}
+
+	if cached.Fingerprint != segment.Cache.fingerprint(segment.env) {
+		log.Debugf("watch files changed for segment: %s, key: %s", segment.Name(), cacheKey)
+		return false
+	}
+
+	err := json.Unmarshal(cached.Writer, &segment.writer)
 	if err != nil {
 		log.Error(err)
 	}
@@ -276,7 +347,16 @@ func (segment *Segment) setCache() {
 		return
 	}
 
-	data, err := json.Marshal(segment.writer)
+	writer, err := json.Marshal(segment.writer)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	data, err := json.Marshal(&cachedSegment{
+		Fingerprint: segment.Cache.fingerprint(segment.env),
+		Writer:      writer,
+	})
 	if err != nil {
 		log.Error(err)
 		return
@@ -285,21 +365,102 @@ func (segment *Segment) setCache() {
 	// TODO: check if we can make segmentwriter a generic Type indicator
 	// that way we can actually get the value straight from cache.Get
 	// and marchalling is obsolete
-	cache.Set(cache.Session, segment.cacheKey(), string(data), segment.Cache.Duration)
+	cache.Set(segment.cacheStore(), segment.cacheKey(), string(data), segment.Cache.Duration)
+}
+
+// setLastKnown stores the writer's state so we can show it again
+// when the segment doesn't make it in time for a next prompt.
+func (segment *Segment) setLastKnown() {
+	if segment.Timeout == 0 && segment.Deadline.IsZero() {
+		return
+	}
+
+	writer, err := json.Marshal(segment.writer)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	data, err := json.Marshal(&lastKnownSegment{
+		Pwd:    segment.env.Pwd(),
+		Writer: writer,
+	})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	cache.Set(cache.Session, segment.lastKnownKey(), string(data), cache.INFINITE)
+}
+
+// restoreLastKnown marks the segment as overrun and renders it using the last known state, marked as stale.
+// As the previous writer is still running, the state is restored into a new one
+// which uses env, as the commands of the abandoned writer are cancelled.
+func (segment *Segment) restoreLastKnown(env runtime.Environment) {
+	segment.Overrun = true
+
+	data, OK := cache.Get[string](cache.Session, segment.lastKnownKey())
+	if !OK {
+		log.Debugf("no last known value for segment: %s", segment.Name())
+		return
+	}
+
+	var lastKnown lastKnownSegment
+	if err := json.Unmarshal([]byte(data), &lastKnown); err != nil {
+		log.Error(err)
+		return
+	}
+
+	if lastKnown.Pwd != env.Pwd() {
+		log.Debugf("no last known value in this folder for segment: %s", segment.Name())
+		return
+	}
+
+	if err := segment.MapSegmentWithWriter(env); err != nil {
+		return
+	}
+
+	if err := json.Unmarshal(lastKnown.Writer, &segment.writer); err != nil {
+		log.Error(err)
+		return
+	}
+
+	segment.writer.SetStale(true)
+	segment.Enabled = true
+	// avoid storing the stale value in the segment's cache
+	segment.restored = true
+
+	template.Cache.AddSegmentData(segment.Name(), segment.writer)
+
+	log.Debug("restored last known value for segment: ", segment.Name())
+}
+
+func (segment *Segment) lastKnownKey() string {
+	return segmentLastKnownKey + segment.Name()
 }
 
 func (segment *Segment) cacheKey() string {
-	format := "segment_cache_%s"
 	switch segment.Cache.Strategy {
 	case Session:
-		return fmt.Sprintf(format, segment.Name())
+		return segmentCacheKey + segment.Name()
+	case Device:
+		return segmentDeviceCacheKey + strings.Join([]string{segment.Name(), segment.folderKey()}, "_")
 	case Folder:
 		fallthrough
 	default:
-		return fmt.Sprintf(format, strings.Join([]string{segment.Name(), segment.folderKey()}, "_"))
+		return segmentCacheKey + strings.Join([]string{segment.Name(), segment.folderKey()}, "_")
 	}
 }
 
+// cacheStore returns the device store for the device strategy, so the value is shared across sessions.
+func (segment *Segment) cacheStore() cache.Store {
+	if segment.Cache.Strategy == Device {
+		return cache.Device
+	}
+
+	return cache.Session
+}
+
 func (segment *Segment) folderKey() string {
 	key, ok := segment.writer.CacheKey()
 	if !ok {
@@ -1,15 +1,25 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/color"
+	"github.com/jandedobbeleer/oh-my-posh/src/maps"
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
 	"github.com/jandedobbeleer/oh-my-posh/src/segments"
+	"github.com/jandedobbeleer/oh-my-posh/src/template"
 
 	"github.com/stretchr/testify/assert"
+	testify_ "github.com/stretchr/testify/mock"
 )
 
 const (
@@ -203,3 +213,247 @@ func TestEvaluateNeeds(t *testing.T) {
 		assert.Equal(t, tc.Needs, tc.Segment.Needs, tc.Case)
 	}
 }
+
+type slowWriter struct {
+	segments.Base
+
+	Value string
+	delay time.Duration
+}
+
+func (s *slowWriter) Template() string {
+	return "{{ .Value }}"
+}
+
+func (s *slowWriter) Enabled() bool {
+	time.Sleep(s.delay)
+	s.Value = "fresh"
+	return true
+}
+
+func TestExecuteLatencyBudget(t *testing.T) {
+	const slow SegmentType = "slow"
+
+	var delay time.Duration
+	Segments[slow] = func() SegmentWriter { return &slowWriter{delay: delay} }
+	defer delete(Segments, slow)
+
+	template.Cache = &cache.Template{Segments: maps.NewConcurrent[any]()}
+	cache.DeleteAll(cache.Session)
+
+	cases := []struct {
+		Case            string
+		Pwd             string
+		Deadline        time.Time
+		Delay           time.Duration
+		Timeout         time.Duration
+		ExpectedEnabled bool
+		ExpectedStale   bool
+		ExpectedOverrun bool
+	}{
+		{Case: "no last known value", Timeout: 10, Delay: 100 * time.Millisecond, ExpectedOverrun: true},
+		{Case: "within the budget", Deadline: time.Now().Add(time.Minute), ExpectedEnabled: true},
+		{Case: "exceeds its timeout", Timeout: 10, Delay: 100 * time.Millisecond, ExpectedEnabled: true, ExpectedStale: true, ExpectedOverrun: true},
+		{Case: "budget exhausted", Deadline: time.Now().Add(-time.Second), ExpectedEnabled: true, ExpectedStale: true, ExpectedOverrun: true},
+		{Case: "last known value of another folder", Pwd: filepath.Join(cwd, "other"), Deadline: time.Now().Add(-time.Second), ExpectedOverrun: true},
+	}
+
+	for _, tc := range cases {
+		delay = tc.Delay
+
+		pwd := tc.Pwd
+		if len(pwd) == 0 {
+			pwd = cwd
+		}
+
+		env := new(mock.Environment)
+		env.On("Flags").Return(&runtime.Flags{})
+		env.On("Pwd").Return(pwd)
+		env.On("DirMatchesOneOf", pwd, []string(nil)).Return(false)
+
+		segment := &Segment{Type: slow, Timeout: tc.Timeout, Deadline: tc.Deadline}
+		segment.Execute(env)
+
+		assert.Equal(t, tc.ExpectedEnabled, segment.Enabled, tc.Case)
+		assert.Equal(t, tc.ExpectedOverrun, segment.Overrun, tc.Case)
+
+		if !tc.ExpectedEnabled {
+			continue
+		}
+
+		writer := segment.writer.(*slowWriter)
+		assert.Equal(t, tc.ExpectedStale, writer.Segment.Stale, tc.Case)
+		assert.Equal(t, "fresh", writer.Value, tc.Case)
+	}
+}
+
+func TestSegmentCacheWatchFiles(t *testing.T) {
+	const slow SegmentType = "slow"
+
+	Segments[slow] = func() SegmentWriter { return &slowWriter{} }
+	defer delete(Segments, slow)
+
+	template.Cache = &cache.Template{Segments: maps.NewConcurrent[any]()}
+	cache.DeleteAll(cache.Device)
+
+	nvmrc := filepath.Join(cwd, ".nvmrc")
+
+	cases := []struct {
+		Case             string
+		Content          string
+		ExpectedRestored bool
+	}{
+		{Case: "nothing cached yet", Content: "20"},
+		{Case: "watch file unchanged", Content: "20", ExpectedRestored: true},
+		{Case: "watch file changed", Content: "22"},
+		{Case: "changed value is cached", Content: "22", ExpectedRestored: true},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Flags").Return(&runtime.Flags{})
+		env.On("Pwd").Return(cwd)
+		env.On("DirMatchesOneOf", cwd, []string(nil)).Return(false)
+		env.On("HasParentFilePath", ".nvmrc", false).Return(&runtime.FileInfo{Path: nvmrc}, nil)
+		env.On("FileContent", nvmrc).Return(tc.Content)
+
+		segment := &Segment{
+			Type: slow,
+			Cache: &Cache{
+				Duration:   cache.ONEDAY,
+				Strategy:   Device,
+				WatchFiles: []string{".nvmrc"},
+			},
+		}
+
+		segment.Execute(env)
+		segment.Render(0, false)
+
+		assert.True(t, segment.Enabled, tc.Case)
+		assert.Equal(t, tc.ExpectedRestored, segment.restored, tc.Case)
+
+		_, OK := cache.Get[string](cache.Device, segmentDeviceCacheKey+"Slow_"+cwd)
+		assert.True(t, OK, tc.Case)
+	}
+}
+
+type commandWriter struct {
+	segments.Base
+
+	env runtime.Environment
+}
+
+func (c *commandWriter) Init(props properties.Properties, env runtime.Environment) {
+	c.Base.Init(props, env)
+	c.env = env
+}
+
+func (c *commandWriter) Template() string {
+	return ""
+}
+
+func (c *commandWriter) Enabled() bool {
+	_, _ = c.env.RunCommand("slow")
+	return true
+}
+
+func TestExecuteCancelsAbandonedCommands(t *testing.T) {
+	const command SegmentType = "command"
+
+	Segments[command] = func() SegmentWriter { return &commandWriter{} }
+	defer delete(Segments, command)
+
+	template.Cache = &cache.Template{Segments: maps.NewConcurrent[any]()}
+
+	cancelled := make(chan bool)
+
+	env := new(mock.Environment)
+	env.On("Flags").Return(&runtime.Flags{})
+	env.On("Pwd").Return(cwd)
+	env.On("DirMatchesOneOf", cwd, []string(nil)).Return(false)
+	env.On("RunCommandWithOptions", testify_.Anything, "slow", []string(nil)).Run(func(args testify_.Arguments) {
+		options := args.Get(0).(*cmd.Options)
+		<-options.Context.Done()
+		close(cancelled)
+	}).Return("", context.Canceled)
+
+	segment := &Segment{Type: command, Timeout: 10}
+	segment.Execute(env)
+
+	assert.True(t, segment.Overrun)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the command of the abandoned segment was not cancelled")
+	}
+}
+
+func TestExecuteReleasesContext(t *testing.T) {
+	const command SegmentType = "command"
+
+	Segments[command] = func() SegmentWriter { return &commandWriter{} }
+	defer delete(Segments, command)
+
+	template.Cache = &cache.Template{Segments: maps.NewConcurrent[any]()}
+
+	var contexts []context.Context
+
+	env := new(mock.Environment)
+	env.On("Flags").Return(&runtime.Flags{})
+	env.On("Pwd").Return(cwd)
+	env.On("DirMatchesOneOf", cwd, []string(nil)).Return(false)
+	env.On("RunCommandWithOptions", testify_.Anything, "slow", []string(nil)).Run(func(args testify_.Arguments) {
+		contexts = append(contexts, args.Get(0).(*cmd.Options).Context)
+	}).Return("", nil)
+
+	segment := &Segment{Type: command, Timeout: 1000}
+	segment.Execute(env)
+
+	assert.True(t, segment.Enabled)
+	assert.False(t, segment.Overrun)
+
+	// like the commands a template runs when rendering the segment
+	segment.writer.Enabled()
+
+	assert.Len(t, contexts, 2)
+	assert.Error(t, contexts[0].Err(), "the context is released")
+	assert.Nil(t, contexts[1], "commands after the release don't use the context")
+}
+
+func TestExecuteWatchFiles(t *testing.T) {
+	const command SegmentType = "command"
+
+	Segments[command] = func() SegmentWriter { return &commandWriter{} }
+	defer delete(Segments, command)
+
+	template.Cache = &cache.Template{Segments: maps.NewConcurrent[any]()}
+
+	home := filepath.Join(cwd, "home")
+	nvmrc := filepath.Join(cwd, ".nvmrc")
+
+	var watchFiles []string
+
+	env := new(mock.Environment)
+	env.On("Flags").Return(&runtime.Flags{})
+	env.On("Pwd").Return(cwd)
+	env.On("Home").Return(home)
+	env.On("DirMatchesOneOf", cwd, []string(nil)).Return(false)
+	env.On("HasParentFilePath", ".nvmrc", false).Return(&runtime.FileInfo{Path: nvmrc}, nil)
+	env.On("HasParentFilePath", "package.json", false).Return(&runtime.FileInfo{}, errors.New("no match at root level"))
+	env.On("RunCommandWithOptions", testify_.Anything, "slow", []string(nil)).Run(func(args testify_.Arguments) {
+		watchFiles = args.Get(0).(*cmd.Options).WatchFiles
+	}).Return("", nil)
+
+	segment := &Segment{
+		Type: command,
+		Cache: &Cache{
+			WatchFiles: []string{".nvmrc", "package.json", "~/.kube/config"},
+		},
+	}
+
+	segment.Execute(env)
+
+	expected := []string{nvmrc, filepath.Join(cwd, "package.json"), filepath.Join(home, ".kube", "config")}
+	assert.Equal(t, expected, watchFiles)
+}
@@ -21,6 +21,7 @@ type SegmentWriter interface {
 	Text() string
 	Init(props properties.Properties, env runtime.Environment)
 	CacheKey() (string, bool)
+	SetStale(stale bool)
 }
 
 func init() {
@@ -44,7 +45,9 @@ func init() {
 	gob.Register(&segments.Cmake{})
 	gob.Register(&segments.Cmd{})
 	gob.Register(&segments.Connection{})
+	gob.Register(&segments.Container{})
 	gob.Register(&segments.Crystal{})
+	gob.Register(&segments.CustomLanguage{})
 	gob.Register(&segments.Dart{})
 	gob.Register(&segments.Deno{})
 	gob.Register(&segments.Docker{})
@@ -191,6 +194,8 @@ const (
 	CMD SegmentType = "command"
 	// CONNECTION writes a connection's information
 	CONNECTION SegmentType = "connection"
+	// CONTAINER writes the container the shell runs in
+	CONTAINER SegmentType = "container"
 	// CRYSTAL writes the active crystal version
 	CRYSTAL SegmentType = "crystal"
 	// DART writes the active dart version
@@ -243,6 +248,8 @@ const (
 	KUBECTL SegmentType = "kubectl"
 	// LASTFM writes the lastfm status
 	LASTFM SegmentType = "lastfm"
+	// LANGUAGE writes the version of a language defined in the config
+	LANGUAGE SegmentType = "language"
 	// LUA writes the active lua version
 	LUA SegmentType = "lua"
 	// MERCURIAL writes Mercurial source control information
@@ -383,6 +390,7 @@ var Segments = map[SegmentType]func() SegmentWriter{
 	CMAKE:           func() SegmentWriter { return &segments.Cmake{} },
 	CMD:             func() SegmentWriter { return &segments.Cmd{} },
 	CONNECTION:      func() SegmentWriter { return &segments.Connection{} },
+	CONTAINER:       func() SegmentWriter { return &segments.Container{} },
 	CRYSTAL:         func() SegmentWriter { return &segments.Crystal{} },
 	DART:            func() SegmentWriter { return &segments.Dart{} },
 	DENO:            func() SegmentWriter { return &segments.Deno{} },
@@ -409,6 +417,7 @@ var Segments = map[SegmentType]func() SegmentWriter{
 	KOTLIN:          func() SegmentWriter { return &segments.Kotlin{} },
 	KUBECTL:         func() SegmentWriter { return &segments.Kubectl{} },
 	LASTFM:          func() SegmentWriter { return &segments.LastFM{} },
+	LANGUAGE:        func() SegmentWriter { return &segments.CustomLanguage{} },
 	LUA:             func() SegmentWriter { return &segments.Lua{} },
 	MERCURIAL:       func() SegmentWriter { return &segments.Mercurial{} },
 	MOJO:            func() SegmentWriter { return &segments.Mojo{} },
@@ -0,0 +1,249 @@
+package duration
+
+import (
+	"fmt"
+	"strconv"
+
+	lang "golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Style how to display a duration
+type Style string
+
+const (
+	// Austin milliseconds short
+	Austin Style = "austin"
+	// Roundrock milliseconds long
+	Roundrock Style = "roundrock"
+	// Dallas milliseconds full
+	Dallas Style = "dallas"
+	// Galveston hour
+	Galveston Style = "galveston"
+	// Galveston hour
+	GalvestonMs Style = "galvestonms"
+	// Houston hour and milliseconds
+	Houston Style = "houston"
+	// Amarillo seconds
+	Amarillo Style = "amarillo"
+	// Round will round the output of the format
+	Round Style = "round"
+	// Always 7 character width
+	Lucky7 Style = "lucky7"
+
+	second           = 1000
+	minute           = 60000
+	hour             = 3600000
+	day              = 86400000
+	secondsPerMinute = 60
+	minutesPerHour   = 60
+	hoursPerDay      = 24
+)
+
+// Format returns the duration in milliseconds using the given style.
+func Format(ms int64, style Style) string {
+	switch style {
+	case Austin:
+		return formatAustin(ms)
+	case Roundrock:
+		return formatRoundrock(ms)
+	case Dallas:
+		return formatDallas(ms)
+	case Galveston:
+		return formatGalveston(ms)
+	case GalvestonMs:
+		return formatGalvestonMs(ms)
+	case Houston:
+		return formatHouston(ms)
+	case Amarillo:
+		return formatAmarillo(ms)
+	case Round:
+		return formatRound(ms)
+	case Lucky7:
+		return formatLucky7(ms)
+	default:
+		return fmt.Sprintf("Style: %s is not available", style)
+	}
+}
+
+func formatAustin(ms int64) string {
+	if ms < second {
+		return fmt.Sprintf("%dms", ms%second)
+	}
+
+	seconds := float64(ms%minute) / second
+	result := strconv.FormatFloat(seconds, 'f', -1, 64) + "s"
+
+	if ms >= minute {
+		result = fmt.Sprintf("%dm %s", ms/minute%secondsPerMinute, result)
+	}
+	if ms >= hour {
+		result = fmt.Sprintf("%dh %s", ms/hour%hoursPerDay, result)
+	}
+	if ms >= day {
+		result = fmt.Sprintf("%dd %s", ms/day, result)
+	}
+	return result
+}
+
+func formatRoundrock(ms int64) string {
+	result := fmt.Sprintf("%dms", ms%second)
+	if ms >= second {
+		result = fmt.Sprintf("%ds %s", ms/second%secondsPerMinute, result)
+	}
+	if ms >= minute {
+		result = fmt.Sprintf("%dm %s", ms/minute%minutesPerHour, result)
+	}
+	if ms >= hour {
+		result = fmt.Sprintf("%dh %s", ms/hour%hoursPerDay, result)
+	}
+	if ms >= day {
+		result = fmt.Sprintf("%dd %s", ms/day, result)
+	}
+	return result
+}
+
+func formatDallas(ms int64) string {
+	seconds := float64(ms%minute) / second
+	result := strconv.FormatFloat(seconds, 'f', -1, 64)
+
+	if ms >= minute {
+		result = fmt.Sprintf("%d:%s", ms/minute%minutesPerHour, result)
+	}
+	if ms >= hour {
+		result = fmt.Sprintf("%d:%s", ms/hour%hoursPerDay, result)
+	}
+	if ms >= day {
+		result = fmt.Sprintf("%d:%s", ms/day, result)
+	}
+	return result
+}
+
+func formatGalveston(ms int64) string {
+	result := fmt.Sprintf("%02d:%02d:%02d", ms/hour, ms/minute%minutesPerHour, ms%minute/second)
+	return result
+}
+
+func formatGalvestonMs(ms int64) string {
+	millies := ms % second
+	result := fmt.Sprintf("%02d:%02d:%02d:%03d", ms/hour, ms/minute%minutesPerHour, ms%minute/second, millies)
+	return result
+}
+
+func formatHouston(ms int64) string {
+	milliseconds := ".0"
+	if ms%second > 0 {
+		// format milliseconds as a string with truncated trailing zeros
+		milliseconds = strconv.FormatFloat(float64(ms%second)/second, 'f', -1, 64)
+		// at this point milliseconds looks like "0.5". remove the leading "0"
+		if len(milliseconds) >= 1 {
+			milliseconds = milliseconds[1:]
+		}
+	}
+
+	result := fmt.Sprintf("%02d:%02d:%02d%s", ms/hour, ms/minute%minutesPerHour, ms%minute/second, milliseconds)
+	return result
+}
+
+func formatAmarillo(ms int64) string {
+	// wholeNumber represents the value to the left of the decimal point (seconds)
+	wholeNumber := ms / second
+	// decimalNumber represents the value to the right of the decimal point (milliseconds)
+	decimalNumber := float64(ms%second) / second
+
+	// format wholeNumber as a string with thousands separators
+	printer := message.NewPrinter(lang.English)
+	result := printer.Sprintf("%d", wholeNumber)
+
+	if decimalNumber > 0 {
+		// format decimalNumber as a string with truncated trailing zeros
+		decimalResult := strconv.FormatFloat(decimalNumber, 'f', -1, 64)
+		// at this point decimalResult looks like "0.5"
+		// remove the leading "0" and append
+		if len(decimalResult) >= 1 {
+			result += decimalResult[1:]
+		}
+	}
+	result += "s"
+
+	return result
+}
+
+func formatRound(ms int64) string {
+	toRoundString := func(one, two int64, oneText, twoText string) string {
+		if two == 0 {
+			return fmt.Sprintf("%d%s", one, oneText)
+		}
+		return fmt.Sprintf("%d%s %d%s", one, oneText, two, twoText)
+	}
+	hours := ms / hour % hoursPerDay
+	if ms >= day {
+		return toRoundString(ms/day, hours, "d", "h")
+	}
+	minutes := ms / minute % secondsPerMinute
+	if ms >= hour {
+		return toRoundString(hours, minutes, "h", "m")
+	}
+	seconds := (ms % minute) / second
+	if ms >= minute {
+		return toRoundString(minutes, seconds, "m", "s")
+	}
+	if ms >= second {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	return fmt.Sprintf("%dms", ms%second)
+}
+
+func formatLucky7(ms int64) string {
+	// https://github.com/JanDeDobbeleer/oh-my-posh/issues/3970
+	// execution time will always be 7 characters long
+	// decimal point will be at the same location (3rd space or str[2])
+	// seconds and milliseconds will be aligned
+	// [m, s], [h, m], [d, h] will be aligned
+	if ms < second {
+		//   999ms
+		// 1234567
+		return fmt.Sprintf("%5dms", ms%second)
+	}
+
+	if ms < minute {
+		// 12.34s
+		// 1234567
+
+		//  1.23s
+		// 1230 (= 1230ms)
+		// ^ use Sprintf pad left space
+		//  1230
+		// from here, just take 1, 23 of 230, and append s and ' '
+
+		result := fmt.Sprintf("%5d", ms)
+
+		return result[:2] + "." + result[2:4] + "s "
+	}
+
+	if ms < hour {
+		m := ms / minute
+		s := ms % minute / second
+
+		return fmt.Sprintf("%2dm %2ds", m, s)
+	}
+
+	if ms < day {
+		h := ms / hour
+		m := ms % hour / minute
+
+		return fmt.Sprintf("%2dh %2dm", h, m)
+	}
+
+	if ms < 100*day {
+		d := ms / day
+		h := ms % day / hour
+
+		return fmt.Sprintf("%2dd %2dh", d, h)
+	}
+
+	// I have no Idea how you got here
+	// return "   ∞   "
+	d := ms / day
+	return fmt.Sprintf("%6dd", d)
+}
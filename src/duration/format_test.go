@@ -0,0 +1,269 @@
+package duration
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDurationAustin(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "1ms"},
+		{Input: "0.1s", Expected: "100ms"},
+		{Input: "1s", Expected: "1s"},
+		{Input: "2.1s", Expected: "2.1s"},
+		{Input: "1m", Expected: "1m 0s"},
+		{Input: "3m2.1s", Expected: "3m 2.1s"},
+		{Input: "1h", Expected: "1h 0m 0s"},
+		{Input: "4h3m2.1s", Expected: "4h 3m 2.1s"},
+		{Input: "124h3m2.1s", Expected: "5d 4h 3m 2.1s"},
+		{Input: "124h3m2.0s", Expected: "5d 4h 3m 2s"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatAustin(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatDurationRoundrock(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "1ms"},
+		{Input: "0.1s", Expected: "100ms"},
+		{Input: "1s", Expected: "1s 0ms"},
+		{Input: "2.1s", Expected: "2s 100ms"},
+		{Input: "1m", Expected: "1m 0s 0ms"},
+		{Input: "3m2.1s", Expected: "3m 2s 100ms"},
+		{Input: "1h", Expected: "1h 0m 0s 0ms"},
+		{Input: "4h3m2.1s", Expected: "4h 3m 2s 100ms"},
+		{Input: "124h3m2.1s", Expected: "5d 4h 3m 2s 100ms"},
+		{Input: "124h3m2.0s", Expected: "5d 4h 3m 2s 0ms"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatRoundrock(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatDallas(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "0.001"},
+		{Input: "0.1s", Expected: "0.1"},
+		{Input: "1s", Expected: "1"},
+		{Input: "2.1s", Expected: "2.1"},
+		{Input: "1m", Expected: "1:0"},
+		{Input: "3m2.1s", Expected: "3:2.1"},
+		{Input: "1h", Expected: "1:0:0"},
+		{Input: "4h3m2.1s", Expected: "4:3:2.1"},
+		{Input: "124h3m2.1s", Expected: "5:4:3:2.1"},
+		{Input: "124h3m2.0s", Expected: "5:4:3:2"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatDallas(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatGalveston(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "00:00:00"},
+		{Input: "0.1s", Expected: "00:00:00"},
+		{Input: "1s", Expected: "00:00:01"},
+		{Input: "2.1s", Expected: "00:00:02"},
+		{Input: "1m", Expected: "00:01:00"},
+		{Input: "3m2.1s", Expected: "00:03:02"},
+		{Input: "1h", Expected: "01:00:00"},
+		{Input: "4h3m2.1s", Expected: "04:03:02"},
+		{Input: "124h3m2.1s", Expected: "124:03:02"},
+		{Input: "124h3m2.0s", Expected: "124:03:02"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatGalveston(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatGalvestonMs(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "00:00:00:001"},
+		{Input: "0.1s", Expected: "00:00:00:100"},
+		{Input: "1s", Expected: "00:00:01:000"},
+		{Input: "2.1s", Expected: "00:00:02:100"},
+		{Input: "1m", Expected: "00:01:00:000"},
+		{Input: "3m2.1s", Expected: "00:03:02:100"},
+		{Input: "1h", Expected: "01:00:00:000"},
+		{Input: "4h3m2.1s", Expected: "04:03:02:100"},
+		{Input: "124h3m2.1s", Expected: "124:03:02:100"},
+		{Input: "124h3m2.0s", Expected: "124:03:02:000"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatGalvestonMs(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output, tc.Input)
+	}
+}
+
+func TestFormatHouston(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "00:00:00.001"},
+		{Input: "0.1s", Expected: "00:00:00.1"},
+		{Input: "1s", Expected: "00:00:01.0"},
+		{Input: "2.1s", Expected: "00:00:02.1"},
+		{Input: "1m", Expected: "00:01:00.0"},
+		{Input: "3m2.1s", Expected: "00:03:02.1"},
+		{Input: "1h", Expected: "01:00:00.0"},
+		{Input: "4h3m2.1s", Expected: "04:03:02.1"},
+		{Input: "124h3m2.1s", Expected: "124:03:02.1"},
+		{Input: "124h3m2.0s", Expected: "124:03:02.0"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatHouston(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatAmarillo(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "0.001s"},
+		{Input: "0.1s", Expected: "0.1s"},
+		{Input: "1s", Expected: "1s"},
+		{Input: "2.1s", Expected: "2.1s"},
+		{Input: "1m", Expected: "60s"},
+		{Input: "3m2.1s", Expected: "182.1s"},
+		{Input: "1h", Expected: "3,600s"},
+		{Input: "4h3m2.1s", Expected: "14,582.1s"},
+		{Input: "124h3m2.1s", Expected: "446,582.1s"},
+		{Input: "124h3m2.0s", Expected: "446,582s"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatAmarillo(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatDurationRound(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "0.001s", Expected: "1ms"},
+		{Input: "0.1s", Expected: "100ms"},
+		{Input: "1s", Expected: "1s"},
+		{Input: "2.1s", Expected: "2s"},
+		{Input: "1m", Expected: "1m"},
+		{Input: "3m2.1s", Expected: "3m 2s"},
+		{Input: "1h", Expected: "1h"},
+		{Input: "4h3m2.1s", Expected: "4h 3m"},
+		{Input: "124h3m2.1s", Expected: "5d 4h"},
+		{Input: "124h3m2.0s", Expected: "5d 4h"},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatRound(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+}
+
+func TestFormatDurationLucky7(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected string
+	}{
+		{
+			Input:    "0.001s",
+			Expected: "    1ms",
+		},
+		{
+			Input:    "0.1s",
+			Expected: "  100ms",
+		},
+		{
+			Input:    "1s",
+			Expected: " 1.00s ",
+		},
+		{
+			Input:    "2.1s",
+			Expected: " 2.10s ",
+		},
+		{
+			Input:    "1m",
+			Expected: " 1m  0s",
+		},
+		{
+			Input:    "3m2.1s",
+			Expected: " 3m  2s",
+		},
+		{
+			Input:    "1h",
+			Expected: " 1h  0m",
+		},
+		{
+			Input:    "4h3m2.1s",
+			Expected: " 4h  3m",
+		},
+		{
+			Input:    "124h3m2.1s",
+			Expected: " 5d  4h",
+		},
+		{
+			Input:    "124h3m2.0s",
+			Expected: " 5d  4h",
+		},
+	}
+
+	for _, tc := range cases {
+		duration, _ := time.ParseDuration(tc.Input)
+		output := formatLucky7(duration.Milliseconds())
+		assert.Equal(t, tc.Expected, output)
+	}
+
+	// Extra fuzz test
+	var timestamp int64 = 1
+	var ms1000days int64 = 1000 * 24 * 60 * 60 * 1000
+
+	// log(ms1000days, 1.5) is approx 62.1
+	for timestamp < ms1000days {
+		timestamp = int64(math.Ceil(float64(timestamp) * 1.5))
+
+		output := formatLucky7(timestamp)
+
+		// Lucky 7!!
+		assert.Equal(t, len(output), 7)
+	}
+}
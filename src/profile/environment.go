@@ -0,0 +1,63 @@
+package profile
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/http"
+)
+
+// WithTracker returns an Environment that measures the commands,
+// file reads and HTTP requests made on env for the tracked segment.
+func WithTracker(tracker *Tracker, env runtime.Environment) runtime.Environment {
+	if tracker == nil {
+		return env
+	}
+
+	return &environment{
+		Environment: env,
+		tracker:     tracker,
+	}
+}
+
+type environment struct {
+	runtime.Environment
+	tracker *Tracker
+}
+
+func (env *environment) RunCommand(command string, args ...string) (string, error) {
+	defer env.tracker.Measure(Command, commandLine(command, args))()
+	return env.Environment.RunCommand(command, args...)
+}
+
+func (env *environment) RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error) {
+	defer env.tracker.Measure(Command, commandLine(command, args))()
+	return env.Environment.RunCommandWithOptions(options, command, args...)
+}
+
+func (env *environment) RunShellCommand(shell, command string) string {
+	defer env.tracker.Measure(Command, commandLine(shell, []string{"-c", command}))()
+	return env.Environment.RunShellCommand(shell, command)
+}
+
+func (env *environment) FileContent(file string) string {
+	defer env.tracker.Measure(File, file)()
+	return env.Environment.FileContent(file)
+}
+
+func (env *environment) LsDir(input string) []fs.DirEntry {
+	defer env.tracker.Measure(File, input)()
+	return env.Environment.LsDir(input)
+}
+
+func (env *environment) HTTPRequest(url string, body io.Reader, timeout int, requestModifiers ...http.RequestModifier) ([]byte, error) {
+	defer env.tracker.Measure(HTTP, url)()
+	return env.Environment.HTTPRequest(url, body, timeout, requestModifiers...)
+}
+
+func commandLine(command string, args []string) string {
+	return strings.TrimSpace(command + " " + strings.Join(args, " "))
+}
@@ -0,0 +1,309 @@
+package profile
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Category string
+
+const (
+	// Segment is the execution of a segment's writer.
+	Segment Category = "segment"
+	// Command is an external command started by a segment.
+	Command Category = "command"
+	// File is a file or folder read by a segment.
+	File Category = "file"
+	// HTTP is an HTTP request made by a segment.
+	HTTP Category = "http"
+	// Template is the rendering of a segment's template.
+	Template Category = "template"
+)
+
+// Span is a timed piece of work, belonging to a segment.
+type Span struct {
+	Start    time.Time
+	Name     string
+	Category Category
+	Segment  string
+	Duration time.Duration
+	// ID identifies the segment, as names aren't unique.
+	ID int
+	// Lane is the goroutine, or worker of the pool, that executed the segment.
+	Lane int
+	// Block is the number of the block the segment belongs to.
+	Block int
+}
+
+func (s *Span) end() time.Time {
+	return s.Start.Add(s.Duration)
+}
+
+// Profile collects the spans of a render. A nil Profile ignores all spans,
+// so callers don't need to check whether profiling is enabled.
+type Profile struct {
+	start    time.Time
+	spans    []*Span
+	segments int
+	blocks   int
+	mu       sync.Mutex
+}
+
+func New() *Profile {
+	return &Profile{
+		start: time.Now(),
+	}
+}
+
+func (p *Profile) add(span *Span) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.spans = append(p.spans, span)
+}
+
+// Block returns the number of the next block that gets rendered.
+func (p *Profile) Block() int {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.blocks++
+
+	return p.blocks
+}
+
+// Segment returns the Tracker which attributes work to a segment of block.
+func (p *Profile) Segment(name string, block int) *Tracker {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.segments++
+
+	return &Tracker{
+		profile: p,
+		segment: name,
+		id:      p.segments,
+		block:   block,
+	}
+}
+
+// Tracker adds the spans of a single segment. A nil Tracker ignores all spans.
+type Tracker struct {
+	profile *Profile
+	segment string
+	id      int
+	block   int
+}
+
+// Executed adds the span of the segment's execution, which started at start on lane.
+func (t *Tracker) Executed(start time.Time, lane int) {
+	if t == nil {
+		return
+	}
+
+	t.profile.add(&Span{
+		Start:    start,
+		Name:     t.segment,
+		Category: Segment,
+		Segment:  t.segment,
+		Duration: time.Since(start),
+		ID:       t.id,
+		Lane:     lane,
+		Block:    t.block,
+	})
+}
+
+// Measure returns a function which adds the span once the work is done.
+func (t *Tracker) Measure(category Category, name string) func() {
+	if t == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+
+	return func() {
+		t.profile.add(&Span{
+			Start:    start,
+			Name:     name,
+			Category: category,
+			Segment:  t.segment,
+			Duration: time.Since(start),
+			ID:       t.id,
+			Block:    t.block,
+		})
+	}
+}
+
+// Spans returns all spans, ordered by their start.
+func (p *Profile) Spans() []*Span {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	spans := slices.Clone(p.spans)
+	p.mu.Unlock()
+
+	slices.SortStableFunc(spans, func(a, b *Span) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return spans
+}
+
+// segmentSpans returns the segment spans, and the work done by every segment.
+func (p *Profile) segmentSpans() ([]*Span, map[int][]*Span) {
+	var segments []*Span
+	work := make(map[int][]*Span)
+
+	for _, span := range p.Spans() {
+		if span.Category == Segment {
+			segments = append(segments, span)
+			continue
+		}
+
+		work[span.ID] = append(work[span.ID], span)
+	}
+
+	// keep the order of the config
+	slices.SortStableFunc(segments, func(a, b *Span) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return segments, work
+}
+
+// CriticalPath returns the segments which determine how long the execution of a block takes.
+// Segments on the same lane run one after the other, so the lane which finishes last is the critical one.
+func (p *Profile) CriticalPath(block int) []*Span {
+	segments, _ := p.segmentSpans()
+
+	var last *Span
+	for _, span := range segments {
+		if span.Block != block {
+			continue
+		}
+
+		if last == nil || span.end().After(last.end()) {
+			last = span
+		}
+	}
+
+	if last == nil {
+		return nil
+	}
+
+	var path []*Span
+	for _, span := range segments {
+		if span.Block == block && span.Lane == last.Lane {
+			path = append(path, span)
+		}
+	}
+
+	return path
+}
+
+// Report returns a flame style breakdown of every segment, followed by the critical path of each block.
+func (p *Profile) Report() string {
+	segments, work := p.segmentSpans()
+	if len(segments) == 0 {
+		return ""
+	}
+
+	var longest time.Duration
+	var nameLength int
+
+	for _, span := range segments {
+		longest = max(longest, span.Duration)
+		nameLength = max(nameLength, len(span.Segment))
+
+		for _, item := range work[span.ID] {
+			nameLength = max(nameLength, len(item.label())+2)
+		}
+	}
+
+	nameLength = min(nameLength, 60)
+
+	var builder strings.Builder
+
+	line := func(name string, duration time.Duration) {
+		if runes := []rune(name); len(runes) > nameLength {
+			name = string(runes[:nameLength-1]) + "…"
+		}
+
+		fmt.Fprintf(&builder, "%-*s %8s %s\n", nameLength, name, formatDuration(duration), bar(duration, longest))
+	}
+
+	for _, span := range segments {
+		line(span.Segment, span.Duration)
+
+		items := work[span.ID]
+		slices.SortStableFunc(items, func(a, b *Span) int {
+			return cmp.Compare(b.Duration, a.Duration)
+		})
+
+		for _, item := range items {
+			line("  "+item.label(), item.Duration)
+		}
+	}
+
+	blocks := make([]int, 0)
+	for _, span := range segments {
+		if !slices.Contains(blocks, span.Block) {
+			blocks = append(blocks, span.Block)
+		}
+	}
+
+	builder.WriteString("\nCritical path:\n\n")
+
+	for _, block := range blocks {
+		path := p.CriticalPath(block)
+
+		var total time.Duration
+		names := make([]string, 0, len(path))
+
+		for _, span := range path {
+			total += span.Duration
+			names = append(names, fmt.Sprintf("%s (%s)", span.Segment, formatDuration(span.Duration)))
+		}
+
+		fmt.Fprintf(&builder, "block %d: %s = %s\n", block, strings.Join(names, " → "), formatDuration(total))
+	}
+
+	return builder.String()
+}
+
+func (s *Span) label() string {
+	return fmt.Sprintf("%s %s", s.Category, s.Name)
+}
+
+func formatDuration(duration time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(duration.Microseconds())/1000)
+}
+
+func bar(duration, longest time.Duration) string {
+	const width = 20
+
+	if longest <= 0 {
+		return ""
+	}
+
+	filled := int(float64(duration) / float64(longest) * width)
+	if filled == 0 && duration > 0 {
+		filled = 1
+	}
+
+	return strings.Repeat("█", filled)
+}
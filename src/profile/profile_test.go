@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProfile() *Profile {
+	p := New()
+	block := p.Block()
+
+	start := p.start
+
+	add := func(name string, lane int, offset, duration time.Duration) *Tracker {
+		tracker := p.Segment(name, block)
+		p.add(&Span{
+			Start:    start.Add(offset),
+			Name:     name,
+			Category: Segment,
+			Segment:  name,
+			Duration: duration,
+			ID:       tracker.id,
+			Lane:     lane,
+			Block:    block,
+		})
+
+		return tracker
+	}
+
+	git := add("Git", 0, 0, 30*time.Millisecond)
+	add("Node", 1, 0, 10*time.Millisecond)
+	add("Go", 1, 10*time.Millisecond, 25*time.Millisecond)
+
+	p.add(&Span{
+		Start:    start.Add(time.Millisecond),
+		Name:     "git status",
+		Category: Command,
+		Segment:  "Git",
+		Duration: 20 * time.Millisecond,
+		ID:       git.id,
+		Block:    block,
+	})
+
+	return p
+}
+
+func TestCriticalPath(t *testing.T) {
+	p := newTestProfile()
+
+	path := p.CriticalPath(1)
+
+	names := make([]string, 0, len(path))
+	for _, span := range path {
+		names = append(names, span.Segment)
+	}
+
+	assert.Equal(t, []string{"Node", "Go"}, names, "the lane that finishes last is the critical path")
+	assert.Empty(t, p.CriticalPath(2))
+}
+
+func TestReport(t *testing.T) {
+	report := newTestProfile().Report()
+
+	assert.Contains(t, report, "Git")
+	assert.Contains(t, report, "  command git status")
+	assert.Contains(t, report, "20.0ms")
+	assert.Contains(t, report, "block 1: Node (10.0ms) → Go (25.0ms) = 35.0ms")
+}
+
+func TestChromeTrace(t *testing.T) {
+	data, err := newTestProfile().ChromeTrace()
+	require.NoError(t, err)
+
+	var result trace
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	lanes := map[string]int{}
+	var command *traceEvent
+
+	for _, event := range result.TraceEvents {
+		if event.Ph != "X" {
+			continue
+		}
+
+		lanes[event.Name] = event.TID
+
+		if event.Cat == string(Command) {
+			command = event
+		}
+	}
+
+	require.NotNil(t, command)
+	assert.Equal(t, lanes["Git"], command.TID, "the work of a segment is on its lane")
+	assert.Equal(t, int64(1000), command.TS)
+	assert.Equal(t, int64(20000), command.Dur)
+	assert.Equal(t, lanes["Node"], lanes["Go"])
+	assert.NotEqual(t, lanes["Git"], lanes["Go"])
+}
+
+func TestNilProfile(t *testing.T) {
+	var p *Profile
+
+	tracker := p.Segment("Git", p.Block())
+	assert.Nil(t, tracker)
+
+	tracker.Measure(Command, "git status")()
+	tracker.Executed(time.Now(), 0)
+
+	assert.Empty(t, p.Report())
+}
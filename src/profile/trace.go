@@ -0,0 +1,88 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// renderLane is the thread used for the template rendering, which happens after
+// all segments are executed, the lanes of the segments follow it.
+const renderLane = 0
+
+type traceEvent struct {
+	Args map[string]any `json:"args,omitempty"`
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	TS   int64          `json:"ts"`
+	Dur  int64          `json:"dur,omitempty"`
+	PID  int            `json:"pid"`
+	TID  int            `json:"tid"`
+}
+
+type trace struct {
+	DisplayTimeUnit string        `json:"displayTimeUnit"`
+	TraceEvents     []*traceEvent `json:"traceEvents"`
+}
+
+// ChromeTrace returns the profile in the Chrome trace event format, which can be opened
+// using chrome://tracing, Perfetto or any other viewer supporting the format.
+func (p *Profile) ChromeTrace() ([]byte, error) {
+	segments, _ := p.segmentSpans()
+
+	// every block has its own lanes, the work of a segment happens on the lane of its execution
+	type laneKey struct {
+		block int
+		lane  int
+	}
+
+	result := &trace{
+		DisplayTimeUnit: "ms",
+		TraceEvents: []*traceEvent{
+			{Name: "thread_name", Ph: "M", PID: 1, TID: renderLane, Args: map[string]any{"name": "render"}},
+		},
+	}
+
+	lanes := map[laneKey]int{}
+	segmentLanes := map[int]int{}
+
+	for _, span := range segments {
+		key := laneKey{span.Block, span.Lane}
+
+		tid, ok := lanes[key]
+		if !ok {
+			tid = len(lanes) + 1
+			lanes[key] = tid
+
+			result.TraceEvents = append(result.TraceEvents, &traceEvent{
+				Name: "thread_name",
+				Ph:   "M",
+				PID:  1,
+				TID:  tid,
+				Args: map[string]any{"name": fmt.Sprintf("block %d, lane %d", key.block, key.lane)},
+			})
+		}
+
+		segmentLanes[span.ID] = tid
+	}
+
+	for _, span := range p.Spans() {
+		tid, ok := segmentLanes[span.ID]
+		if !ok || span.Category == Template {
+			tid = renderLane
+		}
+
+		result.TraceEvents = append(result.TraceEvents, &traceEvent{
+			Name: span.Name,
+			Cat:  string(span.Category),
+			Ph:   "X",
+			TS:   span.Start.Sub(p.start).Microseconds(),
+			Dur:  max(span.Duration.Microseconds(), 1),
+			PID:  1,
+			TID:  tid,
+			Args: map[string]any{"segment": span.Segment, "block": span.Block},
+		})
+	}
+
+	return json.MarshalIndent(result, "", "  ")
+}
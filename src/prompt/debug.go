@@ -1,7 +1,11 @@
 package prompt
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
@@ -66,6 +70,14 @@ func (e *Engine) PrintDebug(startTime time.Time, version string) string {
 		e.write(fmt.Sprintf("%-*s - %3d ms\n", largestSegmentNameLength, segmentName, duration))
 	}
 
+	e.writeOverruns()
+	e.writeCommands()
+
+	if report := e.Profile.Report(); len(report) != 0 {
+		e.write(log.Text("\nProfile:\n\n").Green().Bold().Plain().String())
+		e.write(report)
+	}
+
 	e.write(fmt.Sprintf("\n%s %s\n", log.Text("Run duration:").Green().Bold().Plain(), time.Since(startTime)))
 	e.write(fmt.Sprintf("\n%s %s\n", log.Text("Cache path:").Green().Bold().Plain(), cache.Path()))
 
@@ -76,7 +88,61 @@ func (e *Engine) PrintDebug(startTime time.Time, version string) string {
 
 	e.write(fmt.Sprintf("\n%s %s\n", log.Text("Config path:").Green().Bold().Plain(), cfg))
 
+	if len(e.Config.Errors) != 0 {
+		e.write(log.Text("\nConfig errors:\n\n").Green().Bold().Plain().String())
+		for _, err := range e.Config.Errors {
+			e.write(fmt.Sprintf("%s\n", err))
+		}
+	}
+
 	e.write(log.Text("\nLogs:\n\n").Green().Bold().Plain().String())
 	e.write(e.Env.Logs())
 	return e.string()
 }
+
+// writeOverruns lists the segments that exceeded the latency budget
+// or their timeout in this session, the most frequent first.
+func (e *Engine) writeOverruns() {
+	overruns, OK := cache.Get[map[string]int](cache.Session, cache.OVERRUNCACHE)
+	if !OK || len(overruns) == 0 {
+		return
+	}
+
+	names := slices.SortedFunc(maps.Keys(overruns), func(a, b string) int {
+		if overruns[a] != overruns[b] {
+			return cmp.Compare(overruns[b], overruns[a])
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	e.write(log.Text("\nOverruns:\n\n").Green().Bold().Plain().String())
+
+	for _, name := range names {
+		e.write(fmt.Sprintf("%s - %d times\n", name, overruns[name]))
+	}
+}
+
+// writeCommands lists the commands the segments spawned, in the order they finished.
+// Commands that reused the output of an earlier run are marked as memoized.
+func (e *Engine) writeCommands() {
+	commands := e.Env.Commands()
+	if len(commands) == 0 {
+		return
+	}
+
+	e.write(log.Text("\nCommands:\n\n").Green().Bold().Plain().String())
+
+	for _, command := range commands {
+		line := strings.TrimSpace(fmt.Sprintf("%s %s", command.Command, strings.Join(command.Args, " ")))
+		if command.Dir != "" {
+			line += fmt.Sprintf(" (in %s)", command.Dir)
+		}
+
+		if command.Memoized {
+			line += " (memoized)"
+		}
+
+		e.write(fmt.Sprintf("%4d ms - exit %3d - %s\n", command.Duration.Milliseconds(), command.ExitCode, line))
+	}
+}
@@ -2,11 +2,13 @@ package prompt
 
 import (
 	"strings"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/color"
 	"github.com/jandedobbeleer/oh-my-posh/src/config"
 	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/profile"
 	"github.com/jandedobbeleer/oh-my-posh/src/regex"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
 	"github.com/jandedobbeleer/oh-my-posh/src/shell"
@@ -17,8 +19,10 @@ import (
 var cycle *color.Cycle = &color.Cycle{}
 
 type Engine struct {
+	deadline              time.Time
 	Env                   runtime.Environment
 	Config                *config.Config
+	Profile               *profile.Profile
 	activeSegment         *config.Segment
 	previousActiveSegment *config.Segment
 	rprompt               string
@@ -41,6 +45,9 @@ const (
 	VALID     = "valid"
 	ERROR     = "error"
 	PREVIEW   = "preview"
+	PWD       = "pwd"
+
+	STATUSLINE = "statusline"
 )
 
 func (e *Engine) write(txt string) {
@@ -93,20 +100,34 @@ func (e *Engine) canWriteRightBlock(length int, rprompt bool) (int, bool) {
 }
 
 func (e *Engine) pwd() {
-	// only print when relevant
-	if e.Config.PWD == "" {
+	// elvish and xonsh draw the prompt themselves, their scripts print it using Pwd
+	sh := e.Env.Shell()
+	if sh == shell.ELVISH || sh == shell.XONSH {
 		return
 	}
 
-	// only print when supported
-	sh := e.Env.Shell()
-	if sh == shell.ELVISH || sh == shell.XONSH {
+	// only print when relevant
+	if e.Config.PWD == "" && !e.shellIntegration() {
 		return
 	}
 
-	pwd := e.Env.Pwd()
-	if e.Env.IsCygwin() {
-		pwd = strings.ReplaceAll(pwd, `\`, `/`)
+	if e.shellIntegration() {
+		e.write(terminal.CurrentDirectory(e.currentDirectory()))
+	}
+
+	e.writePwd()
+}
+
+// Pwd returns the sequence which notifies the terminal of the current working directory,
+// for the shells which can't print it as part of the prompt.
+func (e *Engine) Pwd() string {
+	e.writePwd()
+	return e.string()
+}
+
+func (e *Engine) writePwd() {
+	if e.Config.PWD == "" {
+		return
 	}
 
 	// Allow template logic to define when to enable the PWD (when supported)
@@ -117,7 +138,21 @@ func (e *Engine) pwd() {
 
 	user := e.Env.User()
 	host, _ := e.Env.Host()
-	e.write(terminal.Pwd(pwdType, user, host, pwd))
+	e.write(terminal.Pwd(pwdType, user, host, e.currentDirectory()))
+}
+
+func (e *Engine) currentDirectory() string {
+	pwd := e.Env.Pwd()
+	if e.Env.IsCygwin() {
+		pwd = strings.ReplaceAll(pwd, `\`, `/`)
+	}
+
+	return pwd
+}
+
+// shellIntegration reports whether the prompt writes the shell integration marks.
+func (e *Engine) shellIntegration() bool {
+	return e.Config.ShellIntegration && shell.PromptMarks(e.Env.Shell())
 }
 
 func (e *Engine) getNewline() string {
@@ -490,7 +525,17 @@ func New(flags *runtime.Flags) *Engine {
 	reload, _ := cache.Get[bool](cache.Device, config.RELOAD)
 	cfg := config.Get(flags.ConfigPath, reload)
 
+	return NewFromEnvironment(env, cfg)
+}
+
+// NewFromEnvironment sets up the engine to render cfg using env, which allows
+// rendering a prompt from a replayed environment.
+func NewFromEnvironment(env runtime.Environment, cfg *config.Config) *Engine {
+	flags := env.Flags()
+
 	template.Init(env, cfg.Var, cfg.Maps)
+	template.SetPartials(cfg.Templates)
+	template.SetPrecompiled(cfg.CompiledTemplates)
 
 	flags.HasExtra = cfg.DebugPrompt != nil ||
 		cfg.SecondaryPrompt != nil ||
@@ -517,6 +562,10 @@ func New(flags *runtime.Flags) *Engine {
 		prompt:      strings.Builder{},
 	}
 
+	if cfg.LatencyBudget > 0 {
+		eng.deadline = time.Now().Add(time.Duration(cfg.LatencyBudget) * time.Millisecond)
+	}
+
 	// Pre-allocate prompt builder capacity to reduce allocations during rendering
 	eng.prompt.Grow(512) // Start with 512 bytes capacity, will grow as needed
 
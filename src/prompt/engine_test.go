@@ -53,16 +53,23 @@ func TestCanWriteRPrompt(t *testing.T) {
 
 func TestPrintPWD(t *testing.T) {
 	cases := []struct {
-		Case     string
-		Expected string
-		Config   string
-		Pwd      string
-		Shell    string
-		Cygwin   bool
+		Case        string
+		Expected    string
+		Config      string
+		Pwd         string
+		Shell       string
+		Cygwin      bool
+		Integration bool
+		Script      bool
 	}{
 		{Case: "Empty PWD"},
+		{Case: "Shell integration", Integration: true, Expected: "\x1b]633;P;Cwd=pwd\x07"},
+		{Case: "Shell integration - OSC99", Config: terminal.OSC99, Integration: true, Expected: "\x1b]633;P;Cwd=pwd\x07\x1b]9;9;pwd\x1b\\"},
+		{Case: "Shell integration - Nu", Shell: shell.NU, Integration: true},
 		{Case: "OSC99", Config: terminal.OSC99, Expected: "\x1b]9;9;pwd\x1b\\"},
 		{Case: "OSC99 - Elvish", Config: terminal.OSC99, Shell: shell.ELVISH},
+		{Case: "OSC99 - Elvish script", Config: terminal.OSC99, Shell: shell.ELVISH, Script: true, Expected: "\x1b]9;9;pwd\x1b\\"},
+		{Case: "Shell integration - Xonsh script", Shell: shell.XONSH, Integration: true, Script: true},
 		{Case: "OSC7", Config: terminal.OSC7, Expected: "\x1b]7;file://host/pwd\x1b\\"},
 		{Case: "OSC51", Config: terminal.OSC51, Expected: "\x1b]51;Auser@host:pwd\x1b\\"},
 		{Case: "Template (empty)", Config: "{{ if eq .Shell \"pwsh\" }}osc7{{ end }}"},
@@ -107,12 +114,18 @@ func TestPrintPWD(t *testing.T) {
 		engine := &Engine{
 			Env: env,
 			Config: &config.Config{
-				PWD: tc.Config,
+				PWD:              tc.Config,
+				ShellIntegration: tc.Integration,
 			},
 		}
 
-		engine.pwd()
-		got := engine.string()
+		var got string
+		if tc.Script {
+			got = engine.Pwd()
+		} else {
+			engine.pwd()
+			got = engine.string()
+		}
 
 		assert.Equal(t, tc.Expected, got, tc.Case)
 	}
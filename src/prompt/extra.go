@@ -65,7 +65,7 @@ func (e *Engine) ExtraPrompt(promptType ExtraPromptType) string {
 		promptText = fmt.Sprintf("%s%s", e.getNewline(), promptText)
 	}
 
-	if promptType == Transient && e.Config.ShellIntegration {
+	if promptType == Transient && e.shellIntegration() {
 		exitCode, _ := e.Env.StatusCodes()
 		e.write(terminal.CommandFinished(exitCode, e.Env.Flags().NoExitCode))
 		e.write(terminal.PromptStart())
@@ -0,0 +1,63 @@
+package prompt
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/config"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/fixture"
+	"github.com/jandedobbeleer/oh-my-posh/src/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goldenDir = "testdata/golden"
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// TestGolden renders the primary prompt of every case in testdata/golden using the recorded
+// environment, and compares it to the case's golden file. To add a case, record a render using
+//
+//	oh-my-posh debug --config config.omp.json --record fixture.json
+//
+// and write its golden file using go test ./prompt -run TestGolden -update.
+func TestGolden(t *testing.T) {
+	entries, err := os.ReadDir(goldenDir)
+	require.NoError(t, err)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		t.Run(entry.Name(), func(t *testing.T) {
+			dir := filepath.Join(goldenDir, entry.Name())
+
+			recorded, err := fixture.Load(filepath.Join(dir, "fixture.json"))
+			require.NoError(t, err)
+
+			cfg := config.Load(filepath.Join(dir, "config.omp.json"), false)
+
+			// the engine initializes the template cache from the environment
+			template.Cache = nil
+
+			engine := NewFromEnvironment(fixture.NewReplayer(recorded), cfg)
+			got := engine.Primary()
+
+			goldenFile := filepath.Join(dir, "primary.golden")
+
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(goldenFile, []byte(got), 0o644))
+				return
+			}
+
+			expected, err := os.ReadFile(goldenFile)
+			require.NoError(t, err, "run go test ./prompt -run TestGolden -update to create the golden file")
+
+			assert.Equal(t, string(expected), got)
+		})
+	}
+}
@@ -44,7 +44,7 @@ func (e *Engine) Primary() string {
 }
 
 func (e *Engine) writePrimaryPrompt(needsPrimaryRPrompt bool) {
-	if e.Config.ShellIntegration {
+	if e.shellIntegration() {
 		exitCode, _ := e.Env.StatusCodes()
 		e.write(terminal.CommandFinished(exitCode, e.Env.Flags().NoExitCode))
 		e.write(terminal.PromptStart())
@@ -97,7 +97,7 @@ func (e *Engine) writePrimaryPrompt(needsPrimaryRPrompt bool) {
 		e.write(terminal.RenderItermFeatures(e.Config.ITermFeatures, e.Env.Shell(), e.Env.Pwd(), e.Env.User(), host))
 	}
 
-	if e.Config.ShellIntegration {
+	if e.shellIntegration() {
 		e.write(terminal.CommandStart())
 	}
 
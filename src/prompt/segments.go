@@ -2,7 +2,9 @@ package prompt
 
 import (
 	"sync"
+	"time"
 
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/config"
 	"github.com/jandedobbeleer/oh-my-posh/src/terminal"
 )
@@ -26,6 +28,13 @@ func (e *Engine) writeBlockSegments(block *config.Block) (string, int) {
 		return "", 0
 	}
 
+	blockNumber := e.Profile.Block()
+
+	for _, segment := range block.Segments {
+		segment.Deadline = e.deadline
+		segment.Tracker = e.Profile.Segment(segment.Name(), blockNumber)
+	}
+
 	out := make(chan result, length)
 
 	// Use goroutine pool for large numbers of segments to reduce overhead
@@ -36,6 +45,7 @@ func (e *Engine) writeBlockSegments(block *config.Block) (string, int) {
 	}
 
 	e.writeSegments(out, block)
+	e.recordOverruns(block.Segments)
 
 	if e.activeSegment != nil && len(block.TrailingDiamond) > 0 {
 		e.activeSegment.TrailingDiamond = block.TrailingDiamond
@@ -53,7 +63,9 @@ func (e *Engine) writeBlockSegments(block *config.Block) (string, int) {
 func (e *Engine) writeSegmentsConcurrently(segments []*config.Segment, out chan result) {
 	for i, segment := range segments {
 		go func(segment *config.Segment, index int) {
+			start := time.Now()
 			segment.Execute(e.Env)
+			segment.Tracker.Executed(start, index)
 			out <- result{segment, index}
 		}(segment, i)
 	}
@@ -65,10 +77,12 @@ func (e *Engine) writeSegmentsWithPool(segments []*config.Segment, out chan resu
 	var wg sync.WaitGroup
 
 	// Start worker pool
-	for range workerPoolSize {
+	for worker := range workerPoolSize {
 		wg.Go(func() {
 			for task := range tasks {
+				start := time.Now()
 				task.segment.Execute(e.Env)
+				task.segment.Tracker.Executed(start, worker)
 				out <- task
 			}
 		})
@@ -163,3 +177,31 @@ func (e *Engine) canRenderSegment(segment *config.Segment, executed map[string]b
 
 	return true
 }
+
+// recordOverruns counts how many times every segment exceeded the latency budget
+// or its timeout in this session, so the debug command can show the repeat offenders.
+func (e *Engine) recordOverruns(segments []*config.Segment) {
+	var overruns map[string]int
+
+	for _, segment := range segments {
+		if !segment.Overrun {
+			continue
+		}
+
+		if overruns == nil {
+			overruns, _ = cache.Get[map[string]int](cache.Session, cache.OVERRUNCACHE)
+		}
+
+		if overruns == nil {
+			overruns = make(map[string]int)
+		}
+
+		overruns[segment.Name()]++
+	}
+
+	if overruns == nil {
+		return
+	}
+
+	cache.Set(cache.Session, cache.OVERRUNCACHE, overruns, cache.INFINITE)
+}
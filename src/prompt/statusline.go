@@ -0,0 +1,32 @@
+package prompt
+
+import (
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/terminal"
+)
+
+// StatusLine renders the configured status line block using the native
+// format of the given target (tmux, zellij or wezterm) instead of ANSI.
+func (e *Engine) StatusLine(target string) string {
+	block := e.Config.StatusLine
+	if block == nil {
+		log.Debug("no status line block configured")
+		return ""
+	}
+
+	// cache a pointer to the color cycle
+	cycle = &e.Config.Cycle
+
+	text, length := e.writeBlockSegments(block)
+	if length == 0 && !block.Force {
+		return ""
+	}
+
+	statusLine, err := terminal.StatusLine(target, text)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+
+	return statusLine
+}
@@ -0,0 +1,71 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/config"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/terminal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusLine(t *testing.T) {
+	hello := &config.Segment{
+		Type:       "text",
+		Template:   "Hello",
+		Foreground: "red",
+		Background: "blue",
+	}
+
+	// a zero width space renders text without adding to the block length
+	empty := &config.Segment{
+		Type:       "text",
+		Template:   "\u200b",
+		Foreground: "red",
+	}
+
+	cases := []struct {
+		Block    *config.Block
+		Case     string
+		Target   string
+		Expected string
+	}{
+		{
+			Case:   "No status line block",
+			Target: terminal.Tmux,
+		},
+		{
+			Case:   "Empty block",
+			Target: terminal.Tmux,
+			Block:  &config.Block{Segments: []*config.Segment{empty}},
+		},
+		{
+			Case:     "Empty block with force",
+			Target:   terminal.Tmux,
+			Block:    &config.Block{Segments: []*config.Segment{empty}, Force: true},
+			Expected: "#[fg=red]\u200b#[default]",
+		},
+		{
+			Case:     "Tmux",
+			Target:   terminal.Tmux,
+			Block:    &config.Block{Segments: []*config.Segment{hello}},
+			Expected: "#[bg=blue]#[fg=red]Hello#[default]",
+		},
+		{
+			Case:   "Unknown target",
+			Target: "foo",
+			Block:  &config.Block{Segments: []*config.Segment{hello}},
+		},
+	}
+
+	for _, tc := range cases {
+		engine := New(&runtime.Flags{
+			IsPrimary: true,
+		})
+		engine.Config.StatusLine = tc.Block
+
+		got := engine.StatusLine(tc.Target)
+		assert.Equal(t, tc.Expected, got, tc.Case)
+	}
+}
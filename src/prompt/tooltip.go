@@ -6,6 +6,7 @@ import (
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
 	"github.com/jandedobbeleer/oh-my-posh/src/config"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
 	"github.com/jandedobbeleer/oh-my-posh/src/shell"
 	"github.com/jandedobbeleer/oh-my-posh/src/terminal"
 )
@@ -62,6 +63,13 @@ func (e *Engine) Tooltip(tip string) string {
 		e.write(text)
 		e.write(terminal.RestoreCursorPosition())
 		return e.string()
+	case shell.ELVISH:
+		if e.Env.GOOS() != runtime.WINDOWS {
+			// Workaround to align with a right-aligned block on non-Windows systems.
+			text += " "
+		}
+
+		return text
 	default:
 		return text
 	}
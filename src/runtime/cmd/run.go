@@ -3,17 +3,78 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 )
 
+const (
+	// DefaultTimeout is how long a command can run when no timeout is set.
+	DefaultTimeout = 4 * time.Second
+	// waitDelay bounds the wait for the output of child processes that outlive a killed command.
+	waitDelay = 250 * time.Millisecond
+)
+
+// Options change how a command is run. The zero value runs the command
+// in the current working directory, with the current environment and the default timeout.
+type Options struct {
+	// Context cancels the command, killing the process, once it's done.
+	Context context.Context
+	// Dir is the working directory of the command.
+	Dir string
+	// Env contains KEY=value pairs added to the current environment.
+	Env []string
+	// WatchFiles keeps the output across renders until one of these files
+	// is modified, created or removed. Within a render, the output of identical
+	// commands is always shared.
+	WatchFiles []string
+	// Timeout overrides the default timeout.
+	Timeout time.Duration
+}
+
+// Execution describes a command that has been run, for debug purposes.
+type Execution struct {
+	Command  string
+	Dir      string
+	Args     []string
+	Duration time.Duration
+	ExitCode int
+	// Memoized is true when the output of an earlier run was used.
+	Memoized bool
+}
+
 // Run is used to correctly run a command with a timeout.
 func Run(command string, args ...string) (string, error) {
-	// set a timeout of 4 seconds
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+	return RunWithOptions(nil, command, args...)
+}
+
+// RunWithOptions runs a command using the given options, nil uses the defaults.
+func RunWithOptions(options *Options, command string, args ...string) (string, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	parent := options.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = options.Dir
+	cmd.WaitDelay = waitDelay
+	if len(options.Env) != 0 {
+		cmd.Env = append(os.Environ(), options.Env...)
+	}
 	var out bytes.Buffer
 	var err bytes.Buffer
 	cmd.Stdout = &out
@@ -21,6 +82,11 @@ func Run(command string, args ...string) (string, error) {
 	cmdErr := cmd.Run()
 	if cmdErr != nil {
 		output := err.String()
+		// tell a killed command apart from one that failed on its own
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return output, fmt.Errorf("%w: %w", ctxErr, cmdErr)
+		}
+
 		return output, cmdErr
 	}
 	// some silly commands return 0 and the output is in stderr instead of stdout
@@ -31,3 +97,18 @@ func Run(command string, args ...string) (string, error) {
 	output := strings.TrimSpace(result)
 	return output, nil
 }
+
+// ExitCode returns the exit code for the error of a command,
+// -1 when the process could not be started or was killed.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
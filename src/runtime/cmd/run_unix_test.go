@@ -0,0 +1,59 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		Options          *Options
+		Case             string
+		Command          string
+		Expected         string
+		ExpectedExitCode int
+		ExpectedError    bool
+		ExpectedContext  error
+	}{
+		{Case: "default options", Command: "echo hello", Expected: "hello"},
+		{Case: "working directory", Options: &Options{Dir: dir}, Command: "pwd", Expected: dir},
+		{Case: "environment", Options: &Options{Env: []string{"POSH_TEST=value"}}, Command: "echo $POSH_TEST", Expected: "value"},
+		{Case: "exit code", Command: "exit 3", ExpectedExitCode: 3, ExpectedError: true},
+		{Case: "timeout", Options: &Options{Timeout: 10 * time.Millisecond}, Command: "sleep 5", ExpectedExitCode: -1, ExpectedError: true, ExpectedContext: context.DeadlineExceeded},
+		{
+			Case:             "cancelled context",
+			Options:          &Options{Context: cancelled},
+			Command:          "sleep 5",
+			ExpectedExitCode: -1,
+			ExpectedError:    true,
+			ExpectedContext:  context.Canceled,
+		},
+	}
+
+	for _, tc := range cases {
+		start := time.Now()
+		output, err := RunWithOptions(tc.Options, "sh", "-c", tc.Command)
+
+		assert.Less(t, time.Since(start), 4*time.Second, tc.Case)
+		assert.Equal(t, tc.ExpectedError, err != nil, tc.Case)
+		assert.Equal(t, tc.ExpectedExitCode, ExitCode(err), tc.Case)
+
+		if tc.ExpectedContext != nil {
+			assert.ErrorIs(t, err, tc.ExpectedContext, tc.Case)
+		}
+
+		if !tc.ExpectedError {
+			assert.Equal(t, tc.Expected, output, tc.Case)
+		}
+	}
+}
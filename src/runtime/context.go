@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
+)
+
+// WithCancel returns an Environment that runs all commands using a context, so the spawned
+// processes are killed by cancel. Release cancels the context as well, but detaches it first so
+// the commands that follow run as usual. It can't be called while commands are still being started.
+func WithCancel(env Environment) (Environment, context.CancelFunc, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	withCancel := &optionsEnvironment{
+		Environment: env,
+		defaults:    cmd.Options{Context: ctx},
+	}
+
+	release := func() {
+		withCancel.defaults.Context = nil
+		cancel()
+	}
+
+	return withCancel, cancel, release
+}
+
+// WithWatchFiles returns an Environment that keeps the output of all commands
+// across renders, until one of the files is modified, created or removed.
+func WithWatchFiles(files []string, env Environment) Environment {
+	return &optionsEnvironment{
+		Environment: env,
+		defaults:    cmd.Options{WatchFiles: files},
+	}
+}
+
+// optionsEnvironment fills in the options a command doesn't set itself.
+type optionsEnvironment struct {
+	Environment
+	defaults cmd.Options
+}
+
+func (env *optionsEnvironment) RunCommand(command string, args ...string) (string, error) {
+	return env.RunCommandWithOptions(nil, command, args...)
+}
+
+func (env *optionsEnvironment) RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error) {
+	withDefaults := cmd.Options{}
+	if options != nil {
+		withDefaults = *options
+	}
+
+	if withDefaults.Context == nil {
+		withDefaults.Context = env.defaults.Context
+	}
+
+	if len(withDefaults.WatchFiles) == 0 {
+		withDefaults.WatchFiles = env.defaults.WatchFiles
+	}
+
+	return env.Environment.RunCommandWithOptions(&withDefaults, command, args...)
+}
+
+func (env *optionsEnvironment) RunShellCommand(shell, command string) string {
+	if out, err := env.RunCommand(shell, "-c", command); err == nil {
+		return out
+	}
+
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/battery"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/http"
 
 	disk "github.com/shirou/gopsutil/v4/disk"
@@ -46,6 +47,8 @@ type Environment interface {
 	FileContent(file string) string
 	LsDir(input string) []fs.DirEntry
 	RunCommand(command string, args ...string) (string, error)
+	RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error)
+	Commands() []*cmd.Execution
 	RunShellCommand(shell, command string) string
 	ExecutionTime() float64
 	Flags() *Flags
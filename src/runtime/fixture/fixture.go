@@ -0,0 +1,275 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/battery"
+)
+
+// Fixture holds the results of all Environment calls made during a render.
+// Every call is identified by its method and arguments, when the same call
+// is made more than once, the last result is kept.
+type Fixture struct {
+	calls map[string]*Call
+	mu    sync.Mutex
+}
+
+// Call is a single Environment call and its results, as stored in the fixture file.
+type Call struct {
+	Method  string            `json:"method"`
+	Args    json.RawMessage   `json:"args,omitempty"`
+	Results []json.RawMessage `json:"results,omitempty"`
+}
+
+// Load reads a fixture file written by a Recorder.
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []*Call
+	if err := json.Unmarshal(data, &calls); err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{calls: make(map[string]*Call, len(calls))}
+	for _, call := range calls {
+		// the file is indented, the arguments are compared in their compact form
+		if len(call.Args) != 0 {
+			var args bytes.Buffer
+			if err := json.Compact(&args, call.Args); err != nil {
+				return nil, err
+			}
+
+			call.Args = args.Bytes()
+		}
+
+		fixture.calls[key(call.Method, call.Args)] = call
+	}
+
+	return fixture, nil
+}
+
+// Save writes the fixture to path, sorted by method and arguments so fixtures are easy to diff.
+func (f *Fixture) Save(path string) error {
+	f.mu.Lock()
+	calls := make([]*Call, 0, len(f.calls))
+	for _, call := range f.calls {
+		calls = append(calls, call)
+	}
+	f.mu.Unlock()
+
+	slices.SortFunc(calls, func(a, b *Call) int {
+		return strings.Compare(key(a.Method, a.Args), key(b.Method, b.Args))
+	})
+
+	data, err := json.MarshalIndent(calls, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func (f *Fixture) set(method string, args []any, results ...any) error {
+	call := &Call{Method: method}
+
+	var err error
+	if len(args) != 0 {
+		if call.Args, err = json.Marshal(args); err != nil {
+			return err
+		}
+	}
+
+	for _, result := range results {
+		if resultErr, ok := result.(error); ok {
+			result = newError(resultErr)
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		call.Results = append(call.Results, data)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.calls == nil {
+		f.calls = make(map[string]*Call)
+	}
+
+	f.calls[key(method, call.Args)] = call
+
+	return nil
+}
+
+// get restores the results of a call into targets, it returns false when the call wasn't recorded.
+func (f *Fixture) get(method string, args []any, targets ...any) bool {
+	var rawArgs json.RawMessage
+	if len(args) != 0 {
+		rawArgs, _ = json.Marshal(args)
+	}
+
+	f.mu.Lock()
+	call, ok := f.calls[key(method, rawArgs)]
+	f.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	for i, target := range targets {
+		if i >= len(call.Results) {
+			break
+		}
+
+		_ = json.Unmarshal(call.Results[i], target)
+	}
+
+	return true
+}
+
+func key(method string, args json.RawMessage) string {
+	return method + string(args)
+}
+
+// Error is how an error is stored, it keeps the error types segments check for.
+type Error struct {
+	Message  string `json:"message"`
+	Kind     string `json:"kind,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+}
+
+const (
+	commandError        = "command"
+	notImplementedError = "not_implemented"
+	noBatteryError      = "no_battery"
+)
+
+func newError(err error) *Error {
+	result := &Error{Message: err.Error()}
+
+	var cmdErr *runtime.CommandError
+	var notImplemented *runtime.NotImplemented
+	var noBattery *battery.NoBatteryError
+
+	switch {
+	case errors.As(err, &cmdErr):
+		result.Kind = commandError
+		result.ExitCode = cmdErr.ExitCode
+	case errors.As(err, &notImplemented):
+		result.Kind = notImplementedError
+	case errors.As(err, &noBattery):
+		result.Kind = noBatteryError
+	}
+
+	return result
+}
+
+func (e *Error) error() error {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Kind {
+	case commandError:
+		return &runtime.CommandError{Err: e.Message, ExitCode: e.ExitCode}
+	case notImplementedError:
+		return &runtime.NotImplemented{}
+	case noBatteryError:
+		return &battery.NoBatteryError{}
+	default:
+		return errors.New(e.Message)
+	}
+}
+
+// dirEntry stores the fs.DirEntry values returned by LsDir.
+type dirEntry struct {
+	EntryName string      `json:"name"`
+	Mode      fs.FileMode `json:"mode"`
+}
+
+func (d *dirEntry) Name() string {
+	return d.EntryName
+}
+
+func (d *dirEntry) IsDir() bool {
+	return d.Mode.IsDir()
+}
+
+func (d *dirEntry) Type() fs.FileMode {
+	return d.Mode.Type()
+}
+
+func (d *dirEntry) Info() (fs.FileInfo, error) {
+	return &fileInfo{entry: d}, nil
+}
+
+type fileInfo struct {
+	entry *dirEntry
+}
+
+func (f *fileInfo) Name() string {
+	return f.entry.EntryName
+}
+
+func (f *fileInfo) Size() int64 {
+	return 0
+}
+
+func (f *fileInfo) Mode() fs.FileMode {
+	return f.entry.Mode
+}
+
+func (f *fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (f *fileInfo) IsDir() bool {
+	return f.entry.IsDir()
+}
+
+func (f *fileInfo) Sys() any {
+	return nil
+}
+
+func toDirEntries(entries []fs.DirEntry) []*dirEntry {
+	result := make([]*dirEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		item := &dirEntry{EntryName: entry.Name(), Mode: entry.Type()}
+		if info, err := entry.Info(); err == nil {
+			item.Mode = info.Mode()
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+func fromDirEntries(entries []*dirEntry) []fs.DirEntry {
+	if entries == nil {
+		return nil
+	}
+
+	result := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+
+	return result
+}
@@ -0,0 +1,99 @@
+package fixture
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDirEntry struct {
+	name  string
+	isDir bool
+}
+
+func (t *testDirEntry) Name() string {
+	return t.name
+}
+
+func (t *testDirEntry) IsDir() bool {
+	return t.isDir
+}
+
+func (t *testDirEntry) Type() fs.FileMode {
+	if t.isDir {
+		return fs.ModeDir
+	}
+
+	return 0
+}
+
+func (t *testDirEntry) Info() (fs.FileInfo, error) {
+	return nil, errors.New("no info")
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	env := new(mock.Environment)
+	env.On("Getenv", "HOME").Return("/home/posh")
+	env.On("Pwd").Return("/home/posh/project")
+	env.On("Host").Return("", errors.New("no host"))
+	env.On("RunCommand", "node", []string{"--version"}).Return("", &runtime.CommandError{Err: "exit status 2", ExitCode: 2})
+	env.On("HasParentFilePath", "go.mod", false).Return(&runtime.FileInfo{Path: "/home/posh/project/go.mod"}, nil)
+	env.On("LsDir", "/home/posh").Return([]fs.DirEntry{&testDirEntry{name: "project", isDir: true}, &testDirEntry{name: ".bashrc"}})
+	env.On("Flags").Return(&runtime.Flags{Shell: "zsh", ErrorCode: 1})
+
+	recorder := NewRecorder(env, false)
+	recorder.Getenv("HOME")
+	recorder.Pwd()
+	_, _ = recorder.Host()
+	_, _ = recorder.RunCommand("node", "--version")
+	_, _ = recorder.HasParentFilePath("go.mod", false)
+	recorder.LsDir("/home/posh")
+	recorder.Flags()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, recorder.Save(path))
+
+	recorded, err := Load(path)
+	require.NoError(t, err)
+
+	replayer := NewReplayer(recorded)
+
+	assert.Equal(t, "/home/posh", replayer.Getenv("HOME"))
+	assert.Equal(t, "", replayer.Getenv("USER"), "not recorded")
+	assert.Equal(t, "/home/posh/project", replayer.Pwd())
+
+	_, err = replayer.Host()
+	assert.EqualError(t, err, "no host")
+
+	_, err = replayer.RunCommand("node", "--version")
+	var cmdErr *runtime.CommandError
+	if assert.ErrorAs(t, err, &cmdErr) {
+		assert.Equal(t, 2, cmdErr.ExitCode)
+	}
+
+	_, err = replayer.RunCommand("go", "version")
+	assert.ErrorIs(t, err, errNotRecorded)
+
+	info, err := replayer.HasParentFilePath("go.mod", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/posh/project/go.mod", info.Path)
+
+	entries := replayer.LsDir("/home/posh")
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "project", entries[0].Name())
+		assert.True(t, entries[0].IsDir())
+		assert.False(t, entries[1].IsDir())
+	}
+
+	flags := replayer.Flags()
+	assert.Equal(t, "zsh", flags.Shell)
+	assert.Equal(t, 1, flags.ErrorCode)
+	assert.Same(t, flags, replayer.Flags())
+}
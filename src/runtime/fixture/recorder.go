@@ -0,0 +1,335 @@
+package fixture
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/battery"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/http"
+)
+
+// Recorder wraps an Environment and records every call and its results,
+// so the render can be replayed later on using a Replayer.
+// Unless unredacted, credentials in environment variables, files and command
+// output are masked, and HTTP responses are left out.
+type Recorder struct {
+	env        runtime.Environment
+	fixture    *Fixture
+	unredacted bool
+}
+
+func NewRecorder(env runtime.Environment, unredacted bool) *Recorder {
+	return &Recorder{
+		env:        env,
+		fixture:    &Fixture{},
+		unredacted: unredacted,
+	}
+}
+
+// Save writes all recorded calls to path.
+func (r *Recorder) Save(path string) error {
+	return r.fixture.Save(path)
+}
+
+func (r *Recorder) record(method string, args []any, results ...any) {
+	if err := r.fixture.set(method, args, results...); err != nil {
+		log.Error(err)
+	}
+}
+
+func (r *Recorder) Getenv(key string) string {
+	value := r.env.Getenv(key)
+	r.record("Getenv", []any{key}, r.redactVariable(key, value))
+	return value
+}
+
+func (r *Recorder) Pwd() string {
+	value := r.env.Pwd()
+	r.record("Pwd", nil, value)
+	return value
+}
+
+func (r *Recorder) Home() string {
+	value := r.env.Home()
+	r.record("Home", nil, value)
+	return value
+}
+
+func (r *Recorder) User() string {
+	value := r.env.User()
+	r.record("User", nil, value)
+	return value
+}
+
+func (r *Recorder) Root() bool {
+	value := r.env.Root()
+	r.record("Root", nil, value)
+	return value
+}
+
+func (r *Recorder) Host() (string, error) {
+	value, err := r.env.Host()
+	r.record("Host", nil, value, err)
+	return value, err
+}
+
+func (r *Recorder) GOOS() string {
+	value := r.env.GOOS()
+	r.record("GOOS", nil, value)
+	return value
+}
+
+func (r *Recorder) Shell() string {
+	value := r.env.Shell()
+	r.record("Shell", nil, value)
+	return value
+}
+
+func (r *Recorder) Platform() string {
+	value := r.env.Platform()
+	r.record("Platform", nil, value)
+	return value
+}
+
+func (r *Recorder) StatusCodes() (int, string) {
+	code, pipeStatus := r.env.StatusCodes()
+	r.record("StatusCodes", nil, code, pipeStatus)
+	return code, pipeStatus
+}
+
+func (r *Recorder) HasFiles(pattern string) bool {
+	value := r.env.HasFiles(pattern)
+	r.record("HasFiles", []any{pattern}, value)
+	return value
+}
+
+func (r *Recorder) HasFilesInDir(dir, pattern string) bool {
+	value := r.env.HasFilesInDir(dir, pattern)
+	r.record("HasFilesInDir", []any{dir, pattern}, value)
+	return value
+}
+
+func (r *Recorder) HasFolder(folder string) bool {
+	value := r.env.HasFolder(folder)
+	r.record("HasFolder", []any{folder}, value)
+	return value
+}
+
+func (r *Recorder) HasParentFilePath(input string, followSymlinks bool) (*runtime.FileInfo, error) {
+	value, err := r.env.HasParentFilePath(input, followSymlinks)
+	r.record("HasParentFilePath", []any{input, followSymlinks}, value, err)
+	return value, err
+}
+
+func (r *Recorder) HasFileInParentDirs(pattern string, depth uint) bool {
+	value := r.env.HasFileInParentDirs(pattern, depth)
+	r.record("HasFileInParentDirs", []any{pattern, depth}, value)
+	return value
+}
+
+func (r *Recorder) ResolveSymlink(input string) (string, error) {
+	value, err := r.env.ResolveSymlink(input)
+	r.record("ResolveSymlink", []any{input}, value, err)
+	return value, err
+}
+
+func (r *Recorder) DirMatchesOneOf(dir string, regexes []string) bool {
+	value := r.env.DirMatchesOneOf(dir, regexes)
+	r.record("DirMatchesOneOf", []any{dir, regexes}, value)
+	return value
+}
+
+func (r *Recorder) DirIsWritable(input string) bool {
+	value := r.env.DirIsWritable(input)
+	r.record("DirIsWritable", []any{input}, value)
+	return value
+}
+
+func (r *Recorder) CommandPath(command string) string {
+	value := r.env.CommandPath(command)
+	r.record("CommandPath", []any{command}, value)
+	return value
+}
+
+func (r *Recorder) HasCommand(command string) bool {
+	value := r.env.HasCommand(command)
+	r.record("HasCommand", []any{command}, value)
+	return value
+}
+
+func (r *Recorder) FileContent(file string) string {
+	value := r.env.FileContent(file)
+	r.record("FileContent", []any{file}, r.redactContent(value))
+	return value
+}
+
+func (r *Recorder) LsDir(input string) []fs.DirEntry {
+	value := r.env.LsDir(input)
+	r.record("LsDir", []any{input}, toDirEntries(value))
+	return value
+}
+
+func (r *Recorder) RunCommand(command string, args ...string) (string, error) {
+	value, err := r.env.RunCommand(command, args...)
+	r.record("RunCommand", []any{command, args}, r.redactContent(value), err)
+	return value, err
+}
+
+func (r *Recorder) RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error) {
+	value, err := r.env.RunCommandWithOptions(options, command, args...)
+	r.record("RunCommandWithOptions", commandArgs(options, command, args), r.redactContent(value), err)
+	return value, err
+}
+
+func (r *Recorder) Commands() []*cmd.Execution {
+	return r.env.Commands()
+}
+
+func (r *Recorder) RunShellCommand(shell, command string) string {
+	value := r.env.RunShellCommand(shell, command)
+	r.record("RunShellCommand", []any{shell, command}, r.redactContent(value))
+	return value
+}
+
+func (r *Recorder) ExecutionTime() float64 {
+	value := r.env.ExecutionTime()
+	r.record("ExecutionTime", nil, value)
+	return value
+}
+
+func (r *Recorder) Flags() *runtime.Flags {
+	value := r.env.Flags()
+	r.record("Flags", nil, value)
+	return value
+}
+
+func (r *Recorder) BatteryState() (*battery.Info, error) {
+	value, err := r.env.BatteryState()
+	r.record("BatteryState", nil, value, err)
+	return value, err
+}
+
+func (r *Recorder) QueryWindowTitles(processName, windowTitleRegex string) (string, error) {
+	value, err := r.env.QueryWindowTitles(processName, windowTitleRegex)
+	r.record("QueryWindowTitles", []any{processName, windowTitleRegex}, value, err)
+	return value, err
+}
+
+func (r *Recorder) WindowsRegistryKeyValue(key string) (*runtime.WindowsRegistryValue, error) {
+	value, err := r.env.WindowsRegistryKeyValue(key)
+	r.record("WindowsRegistryKeyValue", []any{key}, value, err)
+	return value, err
+}
+
+// HTTPRequest is recorded by url, the body and request modifiers can't be stored.
+// As responses and urls often contain credentials or personal data, they're masked unless unredacted.
+func (r *Recorder) HTTPRequest(url string, body io.Reader, timeout int, requestModifiers ...http.RequestModifier) ([]byte, error) {
+	value, err := r.env.HTTPRequest(url, body, timeout, requestModifiers...)
+
+	if r.unredacted {
+		r.record("HTTPRequest", []any{url}, value, err)
+		return value, err
+	}
+
+	r.record("HTTPRequest", []any{redactURL(url)}, []byte(redacted), err)
+
+	return value, err
+}
+
+func (r *Recorder) IsWsl() bool {
+	value := r.env.IsWsl()
+	r.record("IsWsl", nil, value)
+	return value
+}
+
+func (r *Recorder) IsWsl2() bool {
+	value := r.env.IsWsl2()
+	r.record("IsWsl2", nil, value)
+	return value
+}
+
+func (r *Recorder) IsCygwin() bool {
+	value := r.env.IsCygwin()
+	r.record("IsCygwin", nil, value)
+	return value
+}
+
+func (r *Recorder) StackCount() int {
+	value := r.env.StackCount()
+	r.record("StackCount", nil, value)
+	return value
+}
+
+func (r *Recorder) TerminalWidth() (int, error) {
+	value, err := r.env.TerminalWidth()
+	r.record("TerminalWidth", nil, value, err)
+	return value, err
+}
+
+func (r *Recorder) Logs() string {
+	return r.env.Logs()
+}
+
+func (r *Recorder) InWSLSharedDrive() bool {
+	value := r.env.InWSLSharedDrive()
+	r.record("InWSLSharedDrive", nil, value)
+	return value
+}
+
+func (r *Recorder) ConvertToLinuxPath(input string) string {
+	value := r.env.ConvertToLinuxPath(input)
+	r.record("ConvertToLinuxPath", []any{input}, value)
+	return value
+}
+
+func (r *Recorder) ConvertToWindowsPath(input string) string {
+	value := r.env.ConvertToWindowsPath(input)
+	r.record("ConvertToWindowsPath", []any{input}, value)
+	return value
+}
+
+func (r *Recorder) Connection(connectionType runtime.ConnectionType) (*runtime.Connection, error) {
+	value, err := r.env.Connection(connectionType)
+	r.record("Connection", []any{connectionType}, value, err)
+	return value, err
+}
+
+func (r *Recorder) CursorPosition() (int, int) {
+	row, col := r.env.CursorPosition()
+	r.record("CursorPosition", nil, row, col)
+	return row, col
+}
+
+func (r *Recorder) SystemInfo() (*runtime.SystemInfo, error) {
+	value, err := r.env.SystemInfo()
+	r.record("SystemInfo", nil, value, err)
+	return value, err
+}
+
+func (r *Recorder) redactVariable(key, value string) string {
+	if r.unredacted {
+		return value
+	}
+
+	return redactVariable(key, value)
+}
+
+func (r *Recorder) redactContent(content string) string {
+	if r.unredacted {
+		return content
+	}
+
+	return redactContent(content)
+}
+
+// commandArgs identifies a command with options by what changes its output, the context is left out.
+func commandArgs(options *cmd.Options, command string, args []string) []any {
+	if options == nil {
+		options = &cmd.Options{}
+	}
+
+	return []any{command, args, options.Dir, options.Env}
+}
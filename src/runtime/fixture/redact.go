@@ -0,0 +1,77 @@
+package fixture
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[redacted]"
+
+// secretVariables are the environment variables which hold credentials, matched case-insensitively.
+var secretVariables = []string{
+	"*TOKEN*",
+	"*SECRET*",
+	"*KEY*",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*CREDENTIAL*",
+	"*AUTH*",
+	"AWS_*",
+}
+
+// secretValues matches the values of secret keys in ini, yaml, toml, json and KEY=value content,
+// like aws_secret_access_key = abc, token: abc or "accessToken": "abc".
+var secretValues = regexp.MustCompile(`(?i)("?[\w.-]*(?:token|secret|key|password|passwd|credential|auth)[\w.-]*"?\s*[:=]\s*)("[^"]*"|[^\s,{}\[\]"]+)`)
+
+// isSecretVariable tells whether the environment variable holds a credential.
+func isSecretVariable(key string) bool {
+	key = strings.ToUpper(key)
+
+	for _, pattern := range secretVariables {
+		if match, _ := filepath.Match(pattern, key); match {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redactVariable(key, value string) string {
+	if len(value) == 0 || !isSecretVariable(key) {
+		return value
+	}
+
+	return redacted
+}
+
+// redactContent masks the values of secret keys in a file or command output.
+func redactContent(content string) string {
+	return secretValues.ReplaceAllStringFunc(content, func(match string) string {
+		groups := secretValues.FindStringSubmatch(match)
+		if strings.HasPrefix(groups[2], `"`) {
+			return groups[1] + `"` + redacted + `"`
+		}
+
+		return groups[1] + redacted
+	})
+}
+
+// redactURL masks the query values, which often hold an API key.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+
+	query := u.Query()
+	for key := range query {
+		query.Set(key, redacted)
+	}
+
+	u.RawQuery = query.Encode()
+	u.User = nil
+
+	return u.String()
+}
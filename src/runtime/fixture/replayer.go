@@ -0,0 +1,314 @@
+package fixture
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/battery"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/http"
+)
+
+var errNotRecorded = errors.New("call not recorded")
+
+// Replayer implements Environment using the results stored in a fixture.
+// Calls that weren't recorded return the zero value, or an error when they can.
+type Replayer struct {
+	fixture *Fixture
+	flags   *runtime.Flags
+	once    sync.Once
+}
+
+func NewReplayer(fixture *Fixture) *Replayer {
+	return &Replayer{
+		fixture: fixture,
+	}
+}
+
+func (r *Replayer) replay(method string, args []any, targets ...any) bool {
+	if r.fixture.get(method, args, targets...) {
+		return true
+	}
+
+	log.Debugf("no recorded result for %s%v", method, args)
+	return false
+}
+
+// replayError restores a call which also returns an error.
+func (r *Replayer) replayError(method string, args []any, targets ...any) error {
+	var stored *Error
+	if !r.replay(method, args, append(targets, &stored)...) {
+		return errNotRecorded
+	}
+
+	return stored.error()
+}
+
+func (r *Replayer) Getenv(key string) string {
+	var value string
+	r.replay("Getenv", []any{key}, &value)
+	return value
+}
+
+func (r *Replayer) Pwd() string {
+	var value string
+	r.replay("Pwd", nil, &value)
+	return value
+}
+
+func (r *Replayer) Home() string {
+	var value string
+	r.replay("Home", nil, &value)
+	return value
+}
+
+func (r *Replayer) User() string {
+	var value string
+	r.replay("User", nil, &value)
+	return value
+}
+
+func (r *Replayer) Root() bool {
+	var value bool
+	r.replay("Root", nil, &value)
+	return value
+}
+
+func (r *Replayer) Host() (string, error) {
+	var value string
+	err := r.replayError("Host", nil, &value)
+	return value, err
+}
+
+func (r *Replayer) GOOS() string {
+	var value string
+	r.replay("GOOS", nil, &value)
+	return value
+}
+
+func (r *Replayer) Shell() string {
+	var value string
+	r.replay("Shell", nil, &value)
+	return value
+}
+
+func (r *Replayer) Platform() string {
+	var value string
+	r.replay("Platform", nil, &value)
+	return value
+}
+
+func (r *Replayer) StatusCodes() (int, string) {
+	var code int
+	var pipeStatus string
+	r.replay("StatusCodes", nil, &code, &pipeStatus)
+	return code, pipeStatus
+}
+
+func (r *Replayer) HasFiles(pattern string) bool {
+	var value bool
+	r.replay("HasFiles", []any{pattern}, &value)
+	return value
+}
+
+func (r *Replayer) HasFilesInDir(dir, pattern string) bool {
+	var value bool
+	r.replay("HasFilesInDir", []any{dir, pattern}, &value)
+	return value
+}
+
+func (r *Replayer) HasFolder(folder string) bool {
+	var value bool
+	r.replay("HasFolder", []any{folder}, &value)
+	return value
+}
+
+func (r *Replayer) HasParentFilePath(input string, followSymlinks bool) (*runtime.FileInfo, error) {
+	var value *runtime.FileInfo
+	err := r.replayError("HasParentFilePath", []any{input, followSymlinks}, &value)
+	return value, err
+}
+
+func (r *Replayer) HasFileInParentDirs(pattern string, depth uint) bool {
+	var value bool
+	r.replay("HasFileInParentDirs", []any{pattern, depth}, &value)
+	return value
+}
+
+func (r *Replayer) ResolveSymlink(input string) (string, error) {
+	var value string
+	err := r.replayError("ResolveSymlink", []any{input}, &value)
+	return value, err
+}
+
+func (r *Replayer) DirMatchesOneOf(dir string, regexes []string) bool {
+	var value bool
+	r.replay("DirMatchesOneOf", []any{dir, regexes}, &value)
+	return value
+}
+
+func (r *Replayer) DirIsWritable(input string) bool {
+	var value bool
+	r.replay("DirIsWritable", []any{input}, &value)
+	return value
+}
+
+func (r *Replayer) CommandPath(command string) string {
+	var value string
+	r.replay("CommandPath", []any{command}, &value)
+	return value
+}
+
+func (r *Replayer) HasCommand(command string) bool {
+	var value bool
+	r.replay("HasCommand", []any{command}, &value)
+	return value
+}
+
+func (r *Replayer) FileContent(file string) string {
+	var value string
+	r.replay("FileContent", []any{file}, &value)
+	return value
+}
+
+func (r *Replayer) LsDir(input string) []fs.DirEntry {
+	var value []*dirEntry
+	r.replay("LsDir", []any{input}, &value)
+	return fromDirEntries(value)
+}
+
+func (r *Replayer) RunCommand(command string, args ...string) (string, error) {
+	var value string
+	err := r.replayError("RunCommand", []any{command, args}, &value)
+	return value, err
+}
+
+func (r *Replayer) RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error) {
+	var value string
+	err := r.replayError("RunCommandWithOptions", commandArgs(options, command, args), &value)
+	return value, err
+}
+
+func (r *Replayer) Commands() []*cmd.Execution {
+	return nil
+}
+
+func (r *Replayer) RunShellCommand(shell, command string) string {
+	var value string
+	r.replay("RunShellCommand", []any{shell, command}, &value)
+	return value
+}
+
+func (r *Replayer) ExecutionTime() float64 {
+	var value float64
+	r.replay("ExecutionTime", nil, &value)
+	return value
+}
+
+// Flags always returns the same value, as the flags get updated while rendering.
+func (r *Replayer) Flags() *runtime.Flags {
+	r.once.Do(func() {
+		r.flags = &runtime.Flags{}
+		r.replay("Flags", nil, r.flags)
+	})
+
+	return r.flags
+}
+
+func (r *Replayer) BatteryState() (*battery.Info, error) {
+	var value *battery.Info
+	err := r.replayError("BatteryState", nil, &value)
+	return value, err
+}
+
+func (r *Replayer) QueryWindowTitles(processName, windowTitleRegex string) (string, error) {
+	var value string
+	err := r.replayError("QueryWindowTitles", []any{processName, windowTitleRegex}, &value)
+	return value, err
+}
+
+func (r *Replayer) WindowsRegistryKeyValue(key string) (*runtime.WindowsRegistryValue, error) {
+	var value *runtime.WindowsRegistryValue
+	err := r.replayError("WindowsRegistryKeyValue", []any{key}, &value)
+	return value, err
+}
+
+func (r *Replayer) HTTPRequest(url string, _ io.Reader, _ int, _ ...http.RequestModifier) ([]byte, error) {
+	var value []byte
+	err := r.replayError("HTTPRequest", []any{url}, &value)
+	return value, err
+}
+
+func (r *Replayer) IsWsl() bool {
+	var value bool
+	r.replay("IsWsl", nil, &value)
+	return value
+}
+
+func (r *Replayer) IsWsl2() bool {
+	var value bool
+	r.replay("IsWsl2", nil, &value)
+	return value
+}
+
+func (r *Replayer) IsCygwin() bool {
+	var value bool
+	r.replay("IsCygwin", nil, &value)
+	return value
+}
+
+func (r *Replayer) StackCount() int {
+	var value int
+	r.replay("StackCount", nil, &value)
+	return value
+}
+
+func (r *Replayer) TerminalWidth() (int, error) {
+	var value int
+	err := r.replayError("TerminalWidth", nil, &value)
+	return value, err
+}
+
+func (r *Replayer) Logs() string {
+	return log.String()
+}
+
+func (r *Replayer) InWSLSharedDrive() bool {
+	var value bool
+	r.replay("InWSLSharedDrive", nil, &value)
+	return value
+}
+
+func (r *Replayer) ConvertToLinuxPath(input string) string {
+	var value string
+	r.replay("ConvertToLinuxPath", []any{input}, &value)
+	return value
+}
+
+func (r *Replayer) ConvertToWindowsPath(input string) string {
+	var value string
+	r.replay("ConvertToWindowsPath", []any{input}, &value)
+	return value
+}
+
+func (r *Replayer) Connection(connectionType runtime.ConnectionType) (*runtime.Connection, error) {
+	var value *runtime.Connection
+	err := r.replayError("Connection", []any{connectionType}, &value)
+	return value, err
+}
+
+func (r *Replayer) CursorPosition() (int, int) {
+	var row, col int
+	r.replay("CursorPosition", nil, &row, &col)
+	return row, col
+}
+
+func (r *Replayer) SystemInfo() (*runtime.SystemInfo, error) {
+	var value *runtime.SystemInfo
+	err := r.replayError("SystemInfo", nil, &value)
+	return value, err
+}
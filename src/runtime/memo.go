@@ -0,0 +1,156 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
+)
+
+const commandCacheKey = "command_cache_"
+
+// commandMemo makes sure identical commands only run once per render.
+// Concurrent callers wait for the first one to finish and share its result.
+type commandMemo struct {
+	results map[string]*commandResult
+	mu      sync.Mutex
+}
+
+type commandResult struct {
+	err         error
+	done        chan struct{}
+	output      string
+	interrupted bool
+}
+
+// storedCommand is what we keep across renders, the stamp
+// of the watch files tells us whether the output is still valid.
+type storedCommand struct {
+	Stamp  string `json:"stamp"`
+	Output string `json:"output"`
+}
+
+// run returns the output of fn for key, it only calls fn for the first caller.
+// The boolean tells whether the result was shared from an earlier call.
+// A run that was cancelled or timed out isn't shared, the next caller runs the command again.
+func (m *commandMemo) run(key string, fn func() (string, error)) (string, bool, error) {
+	for {
+		m.mu.Lock()
+
+		if m.results == nil {
+			m.results = make(map[string]*commandResult)
+		}
+
+		if result, ok := m.results[key]; ok {
+			m.mu.Unlock()
+			<-result.done
+
+			if result.interrupted {
+				continue
+			}
+
+			return result.output, true, result.err
+		}
+
+		result := &commandResult{done: make(chan struct{})}
+		m.results[key] = result
+		m.mu.Unlock()
+
+		result.output, result.err = fn()
+
+		if interrupted(result.err) {
+			result.interrupted = true
+			m.mu.Lock()
+			delete(m.results, key)
+			m.mu.Unlock()
+		}
+
+		close(result.done)
+
+		return result.output, false, result.err
+	}
+}
+
+// interrupted tells whether the command was killed because it was cancelled or took too long,
+// which says nothing about the output it would have had.
+func interrupted(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// commandKey identifies an invocation by its command, arguments, working directory, environment and timeout.
+// Without a Dir, the command runs in pwd, so the same command in another folder gets another key.
+func commandKey(options *cmd.Options, pwd, command string, args []string) string {
+	if options == nil {
+		options = &cmd.Options{}
+	}
+
+	dir := options.Dir
+	if len(dir) == 0 {
+		dir = pwd
+	}
+
+	parts := append([]string{command}, args...)
+	parts = append(parts, dir)
+	parts = append(parts, options.Env...)
+	parts = append(parts, options.Timeout.String())
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(parts, "\x00")))
+
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// fileStamp changes whenever one of the files is modified, created or removed.
+func fileStamp(files []string) string {
+	h := fnv.New64a()
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			fmt.Fprintf(h, "%s:missing;", file)
+			continue
+		}
+
+		fmt.Fprintf(h, "%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+	}
+
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// storedOutput returns the output of a previous render when the watch files didn't change.
+func storedOutput(key, stamp string) (string, bool) {
+	data, OK := cache.Get[string](cache.Device, commandCacheKey+key)
+	if !OK {
+		return "", false
+	}
+
+	var stored storedCommand
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		log.Error(err)
+		return "", false
+	}
+
+	if stored.Stamp != stamp {
+		return "", false
+	}
+
+	return stored.Output, true
+}
+
+func storeOutput(key, stamp, output string) {
+	data, err := json.Marshal(&storedCommand{Stamp: stamp, Output: output})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	cache.Set(cache.Device, commandCacheKey+key, string(data), cache.ONEWEEK)
+}
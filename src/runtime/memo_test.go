@@ -0,0 +1,150 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandMemo(t *testing.T) {
+	var memo commandMemo
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+
+	release := make(chan struct{})
+
+	for range 10 {
+		wg.Go(func() {
+			output, _, err := memo.run("key", func() (string, error) {
+				calls.Add(1)
+				<-release
+				return "output", nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "output", output)
+		})
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+
+	_, memoized, _ := memo.run("key", func() (string, error) { return "", nil })
+	assert.True(t, memoized)
+}
+
+func TestCommandMemoInterrupted(t *testing.T) {
+	cases := []struct {
+		Err              error
+		Case             string
+		ExpectedMemoized bool
+	}{
+		{Case: "success", ExpectedMemoized: true},
+		{Case: "failed", Err: errors.New("exit status 1"), ExpectedMemoized: true},
+		{Case: "timed out", Err: fmt.Errorf("%w: signal: killed", context.DeadlineExceeded)},
+		{Case: "cancelled", Err: fmt.Errorf("%w: signal: killed", context.Canceled)},
+	}
+
+	for _, tc := range cases {
+		var memo commandMemo
+
+		_, _, _ = memo.run("key", func() (string, error) { return "output", tc.Err })
+
+		output, memoized, err := memo.run("key", func() (string, error) { return "rerun", nil })
+		assert.Equal(t, tc.ExpectedMemoized, memoized, tc.Case)
+
+		if tc.ExpectedMemoized {
+			assert.Equal(t, "output", output, tc.Case)
+			assert.Equal(t, tc.Err, err, tc.Case)
+			continue
+		}
+
+		assert.Equal(t, "rerun", output, tc.Case)
+		assert.NoError(t, err, tc.Case)
+	}
+}
+
+func TestCommandKey(t *testing.T) {
+	cases := []struct {
+		Options  *cmd.Options
+		Case     string
+		Pwd      string
+		Args     []string
+		Expected bool
+	}{
+		{Case: "identical", Args: []string{"--version"}, Expected: true},
+		{Case: "no options equals the zero value", Options: &cmd.Options{}, Args: []string{"--version"}, Expected: true},
+		{Case: "other arguments", Args: []string{"-v"}},
+		{Case: "other working directory", Options: &cmd.Options{Dir: "/tmp"}, Args: []string{"--version"}},
+		{Case: "working directory equals pwd", Options: &cmd.Options{Dir: "/home/user/repo"}, Args: []string{"--version"}, Expected: true},
+		{Case: "other pwd", Pwd: "/home/user/other", Args: []string{"--version"}},
+		{Case: "other environment", Options: &cmd.Options{Env: []string{"NODE_ENV=test"}}, Args: []string{"--version"}},
+		{Case: "other timeout", Options: &cmd.Options{Timeout: time.Second}, Args: []string{"--version"}},
+	}
+
+	reference := commandKey(nil, "/home/user/repo", "node", []string{"--version"})
+
+	for _, tc := range cases {
+		pwd := tc.Pwd
+		if len(pwd) == 0 {
+			pwd = "/home/user/repo"
+		}
+
+		key := commandKey(tc.Options, pwd, "node", tc.Args)
+		assert.Equal(t, tc.Expected, key == reference, tc.Case)
+	}
+}
+
+func TestStoredOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(file, []byte("a"), 0o644))
+
+	stamp := fileStamp([]string{file})
+	storeOutput("stored", stamp, "output")
+
+	output, OK := storedOutput("stored", stamp)
+	assert.True(t, OK)
+	assert.Equal(t, "output", output)
+
+	assert.NoError(t, os.WriteFile(file, []byte("ab"), 0o644))
+
+	_, OK = storedOutput("stored", fileStamp([]string{file}))
+	assert.False(t, OK, "changed watch file")
+
+	assert.NoError(t, os.Remove(file))
+
+	_, OK = storedOutput("stored", fileStamp([]string{file}))
+	assert.False(t, OK, "removed watch file")
+}
+
+func TestStoredOutputPerFolder(t *testing.T) {
+	// a watch file like ~/.gitconfig is shared by every folder
+	file := filepath.Join(t.TempDir(), ".gitconfig")
+	assert.NoError(t, os.WriteFile(file, []byte("[user]"), 0o644))
+
+	stamp := fileStamp([]string{file})
+	args := []string{"config", "user.email"}
+	options := &cmd.Options{WatchFiles: []string{file}}
+
+	storeOutput(commandKey(options, "/home/user/work", "git", args), stamp, "me@work.com")
+
+	output, OK := storedOutput(commandKey(options, "/home/user/work", "git", args), stamp)
+	assert.True(t, OK)
+	assert.Equal(t, "me@work.com", output)
+
+	_, OK = storedOutput(commandKey(options, "/home/user/personal", "git", args), stamp)
+	assert.False(t, OK, "other folder")
+}
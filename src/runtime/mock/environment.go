@@ -6,6 +6,7 @@ import (
 
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/battery"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/cmd"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/http"
 
 	mock "github.com/stretchr/testify/mock"
@@ -95,6 +96,16 @@ func (env *Environment) RunCommand(command string, args ...string) (string, erro
 	return arguments.String(0), arguments.Error(1)
 }
 
+func (env *Environment) RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error) {
+	arguments := env.Called(options, command, args)
+	return arguments.String(0), arguments.Error(1)
+}
+
+func (env *Environment) Commands() []*cmd.Execution {
+	args := env.Called()
+	return args.Get(0).([]*cmd.Execution)
+}
+
 func (env *Environment) RunShellCommand(shell, command string) string {
 	args := env.Called(shell, command)
 	return args.String(0)
@@ -12,8 +12,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/cache"
@@ -30,12 +32,15 @@ import (
 )
 
 type Terminal struct {
-	CmdFlags *Flags
-	cmdCache *cache.Command
-	lsDirMap *maps.Concurrent[[]fs.DirEntry]
-	cwd      string
-	host     string
-	networks []*Connection
+	CmdFlags    *Flags
+	cmdCache    *cache.Command
+	lsDirMap    *maps.Concurrent[[]fs.DirEntry]
+	cwd         string
+	host        string
+	networks    []*Connection
+	commands    []*cmd.Execution
+	commandMemo commandMemo
+	commandsMu  sync.Mutex
 }
 
 func (term *Terminal) Init(flags *Flags) {
@@ -266,21 +271,78 @@ func (term *Terminal) Home() string {
 }
 
 func (term *Terminal) RunCommand(command string, args ...string) (string, error) {
-	defer log.Trace(time.Now(), append([]string{command}, args...)...)
+	return term.RunCommandWithOptions(nil, command, args...)
+}
+
+func (term *Terminal) RunCommandWithOptions(options *cmd.Options, command string, args ...string) (string, error) {
+	start := time.Now()
+	defer log.Trace(start, append([]string{command}, args...)...)
 
 	if cacheCommand, ok := term.cmdCache.Get(command); ok {
 		command = cacheCommand
 	}
 
-	output, err := cmd.Run(command, args...)
-	if err != nil {
-		log.Error(err)
+	key := commandKey(options, term.Pwd(), command, args)
+
+	var stamp string
+	if options != nil && len(options.WatchFiles) != 0 {
+		stamp = fileStamp(options.WatchFiles)
+		if output, OK := storedOutput(key, stamp); OK {
+			log.Debug(output)
+			term.addCommand(options, &cmd.Execution{Command: command, Args: args, Duration: time.Since(start), Memoized: true})
+			return output, nil
+		}
 	}
 
+	output, memoized, err := term.commandMemo.run(key, func() (string, error) {
+		output, err := cmd.RunWithOptions(options, command, args...)
+		if err != nil {
+			log.Error(err)
+			return output, err
+		}
+
+		if stamp != "" {
+			storeOutput(key, stamp, output)
+		}
+
+		return output, nil
+	})
+
+	term.addCommand(options, &cmd.Execution{
+		Command:  command,
+		Args:     args,
+		Duration: time.Since(start),
+		ExitCode: cmd.ExitCode(err),
+		Memoized: memoized,
+	})
+
 	log.Debug(output)
 	return output, err
 }
 
+// addCommand keeps track of the executed commands in debug mode.
+func (term *Terminal) addCommand(options *cmd.Options, execution *cmd.Execution) {
+	if !term.CmdFlags.Debug {
+		return
+	}
+
+	if options != nil {
+		execution.Dir = options.Dir
+	}
+
+	term.commandsMu.Lock()
+	defer term.commandsMu.Unlock()
+
+	term.commands = append(term.commands, execution)
+}
+
+func (term *Terminal) Commands() []*cmd.Execution {
+	term.commandsMu.Lock()
+	defer term.commandsMu.Unlock()
+
+	return slices.Clone(term.commands)
+}
+
 func (term *Terminal) RunShellCommand(shell, command string) string {
 	defer log.Trace(time.Now())
 
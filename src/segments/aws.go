@@ -3,16 +3,29 @@ package segments
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
 	"github.com/jandedobbeleer/oh-my-posh/src/regex"
+
+	"gopkg.in/ini.v1"
 )
 
 type Aws struct {
 	Base
 
-	Profile string
-	Region  string
+	Expiration time.Time
+	config     *ini.File
+	Profile    string
+	Region     string
+	Endpoint   string
+	RoleName   string
+	AccountID  string
+	// SourceProfile is the profile at the end of the source_profile chain, which holds the credentials
+	SourceProfile string
+	TimeLeft      time.Duration
+	Expired       bool
 }
 
 const (
@@ -42,21 +55,24 @@ func (a *Aws) Enabled() bool {
 	}
 
 	a.Region = getEnvFirstMatch("AWS_REGION", "AWS_DEFAULT_REGION")
-	if len(a.Profile) != 0 && len(a.Region) != 0 {
-		return true
-	}
+	a.Endpoint = a.env.Getenv("AWS_ENDPOINT_URL")
 
-	if a.Profile == "" && len(a.Region) != 0 && displayDefaultUser {
+	a.getConfigFileInfo()
+	if a.Profile == "" && len(a.Region) != 0 {
 		a.Profile = defaultUser
-		return true
 	}
 
-	a.getConfigFileInfo()
 	if !displayDefaultUser && a.Profile == defaultUser {
 		return false
 	}
 
-	return len(a.Profile) != 0
+	if len(a.Profile) == 0 {
+		return false
+	}
+
+	a.setCredentials()
+
+	return true
 }
 
 func (a *Aws) getConfigFileInfo() {
@@ -66,31 +82,55 @@ func (a *Aws) getConfigFileInfo() {
 	}
 
 	config := a.env.FileContent(configPath)
-	configSection := "[default]"
-	if len(a.Profile) != 0 {
-		configSection = fmt.Sprintf("[profile %s]", a.Profile)
+	if len(config) == 0 {
+		return
 	}
 
-	configLines := strings.SplitSeq(config, "\n")
-	var sectionActive bool
-	for line := range configLines {
-		if strings.HasPrefix(line, configSection) {
-			sectionActive = true
-			continue
-		}
+	// the AWS config nests settings for services like s3 below an indented key
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowNestedValues: true, SkipUnrecognizableLines: true}, []byte(config))
+	if err != nil {
+		log.Error(err)
+		return
+	}
 
-		if sectionActive && strings.HasPrefix(line, "region") {
-			splitted := strings.Split(line, "=")
-			if len(splitted) >= 2 {
-				a.Region = strings.TrimSpace(splitted[1])
-				break
-			}
+	a.config = cfg
+
+	profile := a.profileSection(a.Profile)
+	if profile == nil {
+		return
+	}
+
+	if len(a.Region) == 0 {
+		a.Region = profile.Key("region").String()
+	}
+
+	if len(a.Endpoint) == 0 {
+		a.Endpoint = profile.Key("endpoint_url").String()
+	}
+}
+
+// profileSection returns the config section of a profile, the default profile doesn't use the profile prefix.
+func (a *Aws) profileSection(name string) *ini.Section {
+	if a.config == nil {
+		return nil
+	}
+
+	if len(name) == 0 || name == defaultUser {
+		if section, err := a.config.GetSection(defaultUser); err == nil {
+			return section
 		}
 	}
 
-	if a.Profile == "" && len(a.Region) != 0 {
-		a.Profile = defaultUser
+	if len(name) == 0 {
+		return nil
 	}
+
+	section, err := a.config.GetSection("profile " + name)
+	if err != nil {
+		return nil
+	}
+
+	return section
 }
 
 func (a *Aws) RegionAlias() string {
@@ -0,0 +1,278 @@
+package segments
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+
+	"gopkg.in/ini.v1"
+)
+
+// awsCachedCredentials is the credentials file the AWS CLI writes to ~/.aws/cli/cache after assuming a role.
+type awsCachedCredentials struct {
+	Credentials struct {
+		Expiration string `json:"Expiration"`
+	} `json:"Credentials"`
+	AssumedRoleUser struct {
+		Arn string `json:"Arn"`
+	} `json:"AssumedRoleUser"`
+}
+
+// awsSSOToken is the token file the AWS CLI writes to ~/.aws/sso/cache after aws sso login.
+type awsSSOToken struct {
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// setCredentials resolves the role behind the profile and when its credentials expire,
+// using the files the AWS CLI keeps on disk only.
+func (a *Aws) setCredentials() {
+	// credentials exported by aws-vault or aws configure export-credentials
+	for _, key := range []string{"AWS_CREDENTIAL_EXPIRATION", "AWS_SESSION_EXPIRATION"} {
+		if expiration := a.env.Getenv(key); len(expiration) != 0 {
+			a.setExpiration(expiration)
+			break
+		}
+	}
+
+	profile := a.profileSection(a.Profile)
+	if profile == nil {
+		return
+	}
+
+	var assumed *awsCachedCredentials
+
+	switch {
+	case profile.HasKey("role_arn"):
+		a.AccountID, a.RoleName = parseAwsArn(profile.Key("role_arn").String())
+		assumed = a.cachedRoleCredentials(profile)
+	case profile.HasKey("sso_account_id"):
+		a.AccountID = profile.Key("sso_account_id").String()
+		a.RoleName = profile.Key("sso_role_name").String()
+	}
+
+	source := a.sourceProfile(profile)
+
+	if !a.Expiration.IsZero() {
+		return
+	}
+
+	// the SSO session limits every role assumed on top of it
+	if expiration := a.ssoTokenExpiration(source); len(expiration) != 0 {
+		a.setExpiration(expiration)
+		return
+	}
+
+	if assumed != nil {
+		a.setExpiration(assumed.Credentials.Expiration)
+	}
+}
+
+// sourceProfile follows the source_profile chain and returns the section holding the credentials.
+func (a *Aws) sourceProfile(profile *ini.Section) *ini.Section {
+	visited := map[string]bool{a.Profile: true}
+
+	for profile.HasKey("source_profile") {
+		name := profile.Key("source_profile").String()
+		if visited[name] {
+			log.Debugf("source_profile %s is part of a loop", name)
+			return profile
+		}
+
+		visited[name] = true
+
+		source := a.profileSection(name)
+		if source == nil {
+			return profile
+		}
+
+		a.SourceProfile = name
+		profile = source
+	}
+
+	return profile
+}
+
+// cachedRoleCredentials reads the credentials cached for a role, using the same key as the AWS CLI:
+// the SHA1 of the AssumeRole arguments as Python's json.dumps(args, sort_keys=True) writes them.
+// The CLI leaves out the role session name, as it's generated when it isn't configured.
+func (a *Aws) cachedRoleCredentials(profile *ini.Section) *awsCachedCredentials {
+	args := map[string]any{
+		"RoleArn": profile.Key("role_arn").String(),
+	}
+
+	optional := map[string]string{
+		"external_id": "ExternalId",
+		"mfa_serial":  "SerialNumber",
+	}
+
+	for key, arg := range optional {
+		if profile.HasKey(key) {
+			args[arg] = profile.Key(key).String()
+		}
+	}
+
+	if profile.HasKey("duration_seconds") {
+		if duration, err := profile.Key("duration_seconds").Int(); err == nil {
+			args["DurationSeconds"] = duration
+		}
+	}
+
+	key, err := awsCacheArguments(args)
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+
+	file := filepath.Join(a.env.Home(), ".aws", "cli", "cache", awsCacheKey(key)+".json")
+
+	content := a.env.FileContent(file)
+	if len(content) == 0 {
+		return nil
+	}
+
+	var credentials awsCachedCredentials
+	if err := json.Unmarshal([]byte(content), &credentials); err != nil {
+		log.Error(err)
+		return nil
+	}
+
+	if len(credentials.AssumedRoleUser.Arn) != 0 {
+		a.AccountID, a.RoleName = parseAwsArn(credentials.AssumedRoleUser.Arn)
+	}
+
+	return &credentials
+}
+
+// ssoTokenExpiration returns when the SSO token of a profile expires, if it uses SSO.
+// The token is cached under the SHA1 of the sso-session name, or of the start URL for legacy profiles.
+func (a *Aws) ssoTokenExpiration(profile *ini.Section) string {
+	key := profile.Key("sso_start_url").String()
+	if session := profile.Key("sso_session").String(); len(session) != 0 {
+		key = session
+	}
+
+	if len(key) == 0 {
+		return ""
+	}
+
+	file := filepath.Join(a.env.Home(), ".aws", "sso", "cache", awsCacheKey(key)+".json")
+
+	content := a.env.FileContent(file)
+	if len(content) == 0 {
+		return ""
+	}
+
+	var token awsSSOToken
+	if err := json.Unmarshal([]byte(content), &token); err != nil {
+		log.Error(err)
+		return ""
+	}
+
+	return token.ExpiresAt
+}
+
+func (a *Aws) setExpiration(value string) {
+	// older versions of the AWS CLI write the UTC timezone by name
+	value = strings.Replace(value, "UTC", "Z", 1)
+
+	expiration, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	a.Expiration = expiration
+	a.TimeLeft = max(time.Until(expiration), 0)
+	a.Expired = a.TimeLeft == 0
+}
+
+// awsCacheArguments writes the arguments like Python's json.dumps with sorted keys:
+// ", " and ": " as separators, and every non-ASCII character escaped.
+func awsCacheArguments(args map[string]any) (string, error) {
+	keys := slices.Sorted(maps.Keys(args))
+	pairs := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		name, err := pythonJSON(key)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := pythonJSON(args[key])
+		if err != nil {
+			return "", err
+		}
+
+		pairs = append(pairs, name+": "+value)
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}", nil
+}
+
+func pythonJSON(value any) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+
+	for _, r := range strings.TrimSpace(buffer.String()) {
+		if r < utf8.RuneSelf {
+			builder.WriteRune(r)
+			continue
+		}
+
+		if r > 0xFFFF {
+			high, low := utf16.EncodeRune(r)
+			fmt.Fprintf(&builder, `\u%04x\u%04x`, high, low)
+			continue
+		}
+
+		fmt.Fprintf(&builder, `\u%04x`, r)
+	}
+
+	return builder.String(), nil
+}
+
+func awsCacheKey(value string) string {
+	hash := sha1.Sum([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// parseAwsArn returns the account and role name of a role or assumed role ARN,
+// like arn:aws:iam::123456789012:role/path/Admin or arn:aws:sts::123456789012:assumed-role/Admin/session.
+func parseAwsArn(arn string) (account, role string) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return "", ""
+	}
+
+	account = parts[4]
+
+	resource := strings.Split(parts[5], "/")
+	switch resource[0] {
+	case "role":
+		role = resource[len(resource)-1]
+	case "assumed-role":
+		if len(resource) > 1 {
+			role = resource[1]
+		}
+	}
+
+	return account, role
+}
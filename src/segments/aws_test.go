@@ -2,12 +2,14 @@ package segments
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
 
 	"github.com/stretchr/testify/assert"
+	testify_ "github.com/stretchr/testify/mock"
 )
 
 func TestAWSSegment(t *testing.T) {
@@ -67,6 +69,9 @@ func TestAWSSegment(t *testing.T) {
 		env.On("Getenv", "AWS_REGION").Return(tc.Region)
 		env.On("Getenv", "AWS_DEFAULT_REGION").Return(tc.DefaultRegion)
 		env.On("Getenv", "AWS_CONFIG_FILE").Return(tc.ConfigFile)
+		env.On("Getenv", "AWS_ENDPOINT_URL").Return("")
+		env.On("Getenv", "AWS_CREDENTIAL_EXPIRATION").Return("")
+		env.On("Getenv", "AWS_SESSION_EXPIRATION").Return("")
 		env.On("FileContent", "/usr/home/.aws/config").Return("")
 		env.On("Home").Return("/usr/home")
 		props := properties.Map{
@@ -85,3 +90,211 @@ func TestAWSSegment(t *testing.T) {
 		assert.Equal(t, tc.ExpectedString, renderTemplate(env, tc.Template, aws), tc.Case)
 	}
 }
+
+func TestAWSCredentials(t *testing.T) {
+	config := `
+[default]
+region = eu-west-1
+
+[profile admin]
+role_arn = arn:aws:iam::123456789012:role/Admin
+role_session_name = posh
+duration_seconds = 3600
+source_profile = default
+endpoint_url = http://localhost:4566
+s3 =
+  max_concurrent_requests = 20
+
+[profile sso]
+sso_session = posh-sso
+sso_account_id = 210987654321
+sso_role_name = ReadOnly
+region = us-east-1
+
+[profile legacy]
+sso_start_url = https://posh.awsapps.com/start
+sso_account_id = 210987654321
+sso_role_name = ReadOnly
+
+[profile deploy]
+role_arn = arn:aws:iam::111111111111:role/ci/Deploy
+source_profile = sso
+
+[profile loop]
+role_arn = arn:aws:iam::111111111111:role/Loop
+source_profile = loop
+
+[sso-session posh-sso]
+sso_start_url = https://posh.awsapps.com/start
+sso_region = us-east-1
+`
+
+	inAnHour := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	cases := []struct {
+		Case                  string
+		Profile               string
+		Endpoint              string
+		EnvExpiration         string
+		RoleCache             string
+		SSOCache              string
+		LegacySSOCache        string
+		ExpectedRegion        string
+		ExpectedEndpoint      string
+		ExpectedRoleName      string
+		ExpectedAccountID     string
+		ExpectedSourceProfile string
+		ExpectedHasExpiration bool
+		ExpectedExpired       bool
+	}{
+		{Case: "Static credentials", Profile: "default", ExpectedRegion: "eu-west-1"},
+		{
+			Case:                  "Assumed role",
+			Profile:               "admin",
+			ExpectedEndpoint:      "http://localhost:4566",
+			ExpectedRoleName:      "Admin",
+			ExpectedAccountID:     "123456789012",
+			ExpectedSourceProfile: "default",
+		},
+		{
+			Case:     "Assumed role with cached credentials",
+			Profile:  "admin",
+			Endpoint: "http://localhost:4567",
+			RoleCache: `{"Credentials":{"Expiration":"2020-01-01T10:00:00UTC"},` +
+				`"AssumedRoleUser":{"Arn":"arn:aws:sts::123456789012:assumed-role/Admin/posh"}}`,
+			ExpectedEndpoint:      "http://localhost:4567",
+			ExpectedRoleName:      "Admin",
+			ExpectedAccountID:     "123456789012",
+			ExpectedSourceProfile: "default",
+			ExpectedHasExpiration: true,
+			ExpectedExpired:       true,
+		},
+		{
+			Case:                  "SSO session",
+			Profile:               "sso",
+			SSOCache:              `{"expiresAt":"` + inAnHour + `"}`,
+			ExpectedRegion:        "us-east-1",
+			ExpectedRoleName:      "ReadOnly",
+			ExpectedAccountID:     "210987654321",
+			ExpectedHasExpiration: true,
+		},
+		{
+			Case:                  "SSO session expired",
+			Profile:               "sso",
+			SSOCache:              `{"expiresAt":"2020-01-01T10:00:00Z"}`,
+			ExpectedRegion:        "us-east-1",
+			ExpectedRoleName:      "ReadOnly",
+			ExpectedAccountID:     "210987654321",
+			ExpectedHasExpiration: true,
+			ExpectedExpired:       true,
+		},
+		{
+			Case:                  "Legacy SSO",
+			Profile:               "legacy",
+			LegacySSOCache:        `{"expiresAt":"2020-01-01T10:00:00Z"}`,
+			ExpectedRoleName:      "ReadOnly",
+			ExpectedAccountID:     "210987654321",
+			ExpectedHasExpiration: true,
+			ExpectedExpired:       true,
+		},
+		{
+			Case:                  "Role on top of SSO",
+			Profile:               "deploy",
+			SSOCache:              `{"expiresAt":"2020-01-01T10:00:00Z"}`,
+			ExpectedRoleName:      "Deploy",
+			ExpectedAccountID:     "111111111111",
+			ExpectedSourceProfile: "sso",
+			ExpectedHasExpiration: true,
+			ExpectedExpired:       true,
+		},
+		{
+			Case:                  "Exported credentials",
+			Profile:               "default",
+			EnvExpiration:         inAnHour,
+			ExpectedRegion:        "eu-west-1",
+			ExpectedHasExpiration: true,
+		},
+		{Case: "Source profile loop", Profile: "loop", ExpectedRoleName: "Loop", ExpectedAccountID: "111111111111"},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Getenv", "AWS_VAULT").Return("")
+		env.On("Getenv", "AWS_PROFILE").Return(tc.Profile)
+		env.On("Getenv", "AWS_DEFAULT_PROFILE").Return("")
+		env.On("Getenv", "AWS_REGION").Return("")
+		env.On("Getenv", "AWS_DEFAULT_REGION").Return("")
+		env.On("Getenv", "AWS_CONFIG_FILE").Return("")
+		env.On("Getenv", "AWS_ENDPOINT_URL").Return(tc.Endpoint)
+		env.On("Getenv", "AWS_CREDENTIAL_EXPIRATION").Return(tc.EnvExpiration)
+		env.On("Getenv", "AWS_SESSION_EXPIRATION").Return("")
+		env.On("Home").Return("/usr/home")
+		env.On("FileContent", "/usr/home/.aws/config").Return(config)
+		env.On("FileContent", "/usr/home/.aws/cli/cache/d01f726b6165762f6f71e742b03b1bc031b82dbf.json").Return(tc.RoleCache)
+		env.On("FileContent", "/usr/home/.aws/sso/cache/3880b29acd47e6dec905682c3b804693f4dbeb95.json").Return(tc.SSOCache)
+		env.On("FileContent", "/usr/home/.aws/sso/cache/9ae283ef2790daf7687b5ca550be31eb1597a3ee.json").Return(tc.LegacySSOCache)
+		env.On("FileContent", testify_.Anything).Return("")
+
+		aws := &Aws{}
+		aws.Init(properties.Map{}, env)
+
+		assert.True(t, aws.Enabled(), tc.Case)
+		assert.Equal(t, tc.ExpectedRegion, aws.Region, tc.Case)
+		assert.Equal(t, tc.ExpectedEndpoint, aws.Endpoint, tc.Case)
+		assert.Equal(t, tc.ExpectedRoleName, aws.RoleName, tc.Case)
+		assert.Equal(t, tc.ExpectedAccountID, aws.AccountID, tc.Case)
+		assert.Equal(t, tc.ExpectedSourceProfile, aws.SourceProfile, tc.Case)
+		assert.Equal(t, tc.ExpectedHasExpiration, !aws.Expiration.IsZero(), tc.Case)
+		assert.Equal(t, tc.ExpectedExpired, aws.Expired, tc.Case)
+
+		if tc.ExpectedHasExpiration && !tc.ExpectedExpired {
+			assert.InDelta(t, time.Hour, aws.TimeLeft, float64(time.Minute), tc.Case)
+		}
+	}
+}
+
+func TestParseAwsArn(t *testing.T) {
+	cases := []struct {
+		Case            string
+		Arn             string
+		ExpectedAccount string
+		ExpectedRole    string
+	}{
+		{Case: "Invalid"},
+		{Case: "Role", Arn: "arn:aws:iam::123456789012:role/Admin", ExpectedAccount: "123456789012", ExpectedRole: "Admin"},
+		{Case: "Role with path", Arn: "arn:aws:iam::123456789012:role/teams/ci/Deploy", ExpectedAccount: "123456789012", ExpectedRole: "Deploy"},
+		{Case: "Assumed role", Arn: "arn:aws:sts::123456789012:assumed-role/Admin/posh", ExpectedAccount: "123456789012", ExpectedRole: "Admin"},
+		{Case: "User", Arn: "arn:aws:iam::123456789012:user/jan", ExpectedAccount: "123456789012"},
+	}
+
+	for _, tc := range cases {
+		account, role := parseAwsArn(tc.Arn)
+		assert.Equal(t, tc.ExpectedAccount, account, tc.Case)
+		assert.Equal(t, tc.ExpectedRole, role, tc.Case)
+	}
+}
+
+func TestAwsCacheArguments(t *testing.T) {
+	cases := []struct {
+		Args     map[string]any
+		Case     string
+		Expected string
+	}{
+		{
+			Case:     "Role",
+			Args:     map[string]any{"RoleArn": "arn:aws:iam::123456789012:role/Admin", "DurationSeconds": 3600},
+			Expected: `{"DurationSeconds": 3600, "RoleArn": "arn:aws:iam::123456789012:role/Admin"}`,
+		},
+		{
+			Case:     "Non-ASCII",
+			Args:     map[string]any{"RoleArn": "arn:aws:iam::123456789012:role/Admin", "ExternalId": "<café & 😀>"},
+			Expected: `{"ExternalId": "<caf\u00e9 & \ud83d\ude00>", "RoleArn": "arn:aws:iam::123456789012:role/Admin"}`,
+		},
+	}
+
+	for _, tc := range cases {
+		got, err := awsCacheArguments(tc.Args)
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, got, tc.Case)
+	}
+}
@@ -15,6 +15,7 @@ type Base struct {
 type Segment struct {
 	Text  string
 	Index int
+	Stale bool
 }
 
 func (b *Base) Text() string {
@@ -29,6 +30,10 @@ func (b *Base) SetIndex(index int) {
 	b.Segment.Index = index
 }
 
+func (b *Base) SetStale(stale bool) {
+	b.Segment.Stale = stale
+}
+
 func (b *Base) Init(props properties.Properties, env runtime.Environment) {
 	b.Segment = &Segment{}
 	b.props = props
@@ -0,0 +1,140 @@
+package segments
+
+import (
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+)
+
+type Container struct {
+	Base
+
+	// Runtime runs the container: docker, podman, containerd, kubernetes, lxc or systemd-nspawn
+	Runtime string
+	// Tool created the container: codespaces, devcontainer, toolbox or distrobox
+	Tool      string
+	Name      string
+	Image     string
+	Namespace string
+}
+
+const (
+	kubernetesNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+func (c *Container) Template() string {
+	return " \uf4b7 {{ if .Tool }}{{ .Tool }}{{ else }}{{ .Runtime }}{{ end }}{{ if .Name }} {{ .Name }}{{ end }} "
+}
+
+func (c *Container) Enabled() bool {
+	if c.env.GOOS() != runtime.LINUX {
+		return false
+	}
+
+	c.setRuntime()
+	c.setTool()
+
+	if len(c.Runtime) == 0 && len(c.Tool) == 0 {
+		return false
+	}
+
+	// docker and kubernetes use the container ID or pod name as the hostname
+	if len(c.Name) == 0 && (c.Runtime == "docker" || c.Runtime == "kubernetes") {
+		c.Name, _ = c.env.Host()
+	}
+
+	return true
+}
+
+func (c *Container) setRuntime() {
+	if len(c.env.Getenv("KUBERNETES_SERVICE_HOST")) != 0 {
+		c.Runtime = "kubernetes"
+		c.Namespace = strings.TrimSpace(c.env.FileContent(kubernetesNamespaceFile))
+		return
+	}
+
+	// podman, and the tools built on top of it, describe the container in /run/.containerenv
+	if c.env.HasFilesInDir("/run", ".containerenv") {
+		c.parseContainerEnv(c.env.FileContent("/run/.containerenv"))
+		return
+	}
+
+	if c.env.HasFilesInDir("/", ".dockerenv") {
+		c.Runtime = "docker"
+		return
+	}
+
+	// set by systemd-nspawn, lxc and podman
+	if container := c.env.Getenv("container"); len(container) != 0 {
+		c.Runtime = container
+		return
+	}
+
+	c.Runtime = cgroupRuntime(c.env.FileContent("/proc/1/cgroup"))
+}
+
+// parseContainerEnv reads the key="value" pairs of /run/.containerenv, which podman only fills in for
+// privileged containers, or when they are created by toolbox or distrobox.
+func (c *Container) parseContainerEnv(content string) {
+	c.Runtime = "podman"
+
+	for line := range strings.SplitSeq(content, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+
+		value = strings.Trim(value, `"`)
+
+		switch key {
+		case "engine":
+			// like podman-4.9.3
+			engine, _, _ := strings.Cut(value, "-")
+			c.Runtime = engine
+		case "name":
+			c.Name = value
+		case "image":
+			c.Image = value
+		}
+	}
+}
+
+// cgroupRuntime detects the runtime from the cgroup of the init process, which is only
+// informative with cgroup v1 as v2 shows 0::/ inside a container.
+func cgroupRuntime(cgroup string) string {
+	runtimes := []struct {
+		marker  string
+		runtime string
+	}{
+		{marker: "/kubepods", runtime: "kubernetes"},
+		{marker: "/docker", runtime: "docker"},
+		{marker: "/libpod", runtime: "podman"},
+		{marker: "/containerd", runtime: "containerd"},
+		{marker: "/lxc", runtime: "lxc"},
+	}
+
+	for _, r := range runtimes {
+		if strings.Contains(cgroup, r.marker) {
+			return r.runtime
+		}
+	}
+
+	return ""
+}
+
+func (c *Container) setTool() {
+	switch {
+	case c.env.Getenv("CODESPACES") == "true":
+		c.Tool = "codespaces"
+		c.Name = c.env.Getenv("CODESPACE_NAME")
+	case c.env.Getenv("REMOTE_CONTAINERS") == "true":
+		c.Tool = "devcontainer"
+	case len(c.env.Getenv("DISTROBOX_ENTER_PATH")) != 0:
+		c.Tool = "distrobox"
+		if name := c.env.Getenv("CONTAINER_ID"); len(name) != 0 {
+			c.Name = name
+		}
+	case len(c.env.Getenv("TOOLBOX_PATH")) != 0 || c.env.HasFilesInDir("/run", ".toolboxenv"):
+		c.Tool = "toolbox"
+	}
+}
@@ -0,0 +1,110 @@
+package segments
+
+import (
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+	testify_ "github.com/stretchr/testify/mock"
+)
+
+func TestContainer(t *testing.T) {
+	cases := []struct {
+		Env               map[string]string
+		Case              string
+		GOOS              string
+		ContainerEnv      string
+		Cgroup            string
+		Namespace         string
+		ExpectedString    string
+		ExpectedImage     string
+		ExpectedNamespace string
+		DockerEnv         bool
+		ToolboxEnv        bool
+		ExpectedEnabled   bool
+	}{
+		{Case: "Not in a container"},
+		{Case: "Windows", GOOS: runtime.WINDOWS, DockerEnv: true},
+		{Case: "Docker", DockerEnv: true, ExpectedEnabled: true, ExpectedString: "docker 4f2a9c1b7e3d"},
+		{
+			Case:            "Devcontainer",
+			DockerEnv:       true,
+			Env:             map[string]string{"REMOTE_CONTAINERS": "true"},
+			ExpectedEnabled: true,
+			ExpectedString:  "devcontainer 4f2a9c1b7e3d",
+		},
+		{
+			Case:            "Codespaces",
+			DockerEnv:       true,
+			Env:             map[string]string{"CODESPACES": "true", "CODESPACE_NAME": "posh-dev-x7q9"},
+			ExpectedEnabled: true,
+			ExpectedString:  "codespaces posh-dev-x7q9",
+		},
+		{
+			Case:              "Kubernetes pod",
+			Env:               map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			Namespace:         "posh\n",
+			ExpectedEnabled:   true,
+			ExpectedString:    "kubernetes 4f2a9c1b7e3d",
+			ExpectedNamespace: "posh",
+		},
+		{
+			Case:            "Toolbox",
+			ContainerEnv:    "engine=\"podman-4.9.3\"\nname=\"fedora-toolbox-39\"\nimage=\"registry.fedoraproject.org/fedora-toolbox:39\"\n",
+			ToolboxEnv:      true,
+			ExpectedEnabled: true,
+			ExpectedString:  "toolbox fedora-toolbox-39",
+			ExpectedImage:   "registry.fedoraproject.org/fedora-toolbox:39",
+		},
+		{
+			Case:            "Distrobox",
+			ContainerEnv:    "engine=\"podman-5.0.0\"\nname=\"arch\"\n",
+			Env:             map[string]string{"DISTROBOX_ENTER_PATH": "/usr/bin/distrobox-enter", "CONTAINER_ID": "archbox"},
+			ExpectedEnabled: true,
+			ExpectedString:  "distrobox archbox",
+		},
+		{Case: "Rootless podman", ContainerEnv: "\n", ExpectedEnabled: true, ExpectedString: "podman"},
+		{Case: "systemd-nspawn", Env: map[string]string{"container": "systemd-nspawn"}, ExpectedEnabled: true, ExpectedString: "systemd-nspawn"},
+		{Case: "containerd cgroup", Cgroup: "12:pids:/containerd/4f2a9c1b\n1:name=systemd:/containerd/4f2a9c1b\n", ExpectedEnabled: true, ExpectedString: "containerd"},
+		{Case: "cgroup v2", Cgroup: "0::/\n"},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+
+		goos := tc.GOOS
+		if len(goos) == 0 {
+			goos = runtime.LINUX
+		}
+
+		env.On("GOOS").Return(goos)
+
+		for key, value := range tc.Env {
+			env.On("Getenv", key).Return(value)
+		}
+
+		env.On("Getenv", testify_.Anything).Return("")
+		env.On("HasFilesInDir", "/", ".dockerenv").Return(tc.DockerEnv)
+		env.On("HasFilesInDir", "/run", ".containerenv").Return(len(tc.ContainerEnv) != 0)
+		env.On("HasFilesInDir", "/run", ".toolboxenv").Return(tc.ToolboxEnv)
+		env.On("FileContent", "/run/.containerenv").Return(tc.ContainerEnv)
+		env.On("FileContent", "/proc/1/cgroup").Return(tc.Cgroup)
+		env.On("FileContent", kubernetesNamespaceFile).Return(tc.Namespace)
+		env.On("Host").Return("4f2a9c1b7e3d", nil)
+
+		c := &Container{}
+		c.Init(properties.Map{}, env)
+
+		assert.Equal(t, tc.ExpectedEnabled, c.Enabled(), tc.Case)
+		if !tc.ExpectedEnabled {
+			continue
+		}
+
+		assert.Equal(t, tc.ExpectedString, renderTemplate(env, "{{ if .Tool }}{{ .Tool }}{{ else }}{{ .Runtime }}{{ end }}{{ if .Name }} {{ .Name }}{{ end }}", c), tc.Case)
+		assert.Equal(t, tc.ExpectedImage, c.Image, tc.Case)
+		assert.Equal(t, tc.ExpectedNamespace, c.Namespace, tc.Case)
+	}
+}
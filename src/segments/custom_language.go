@@ -0,0 +1,92 @@
+package segments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/regex"
+)
+
+// CustomLanguage is a language segment defined entirely by its properties,
+// for toolchains which don't have a segment of their own.
+type CustomLanguage struct {
+	Language
+}
+
+const (
+	// LanguageExecutable is the executable which prints the version
+	LanguageExecutable properties.Property = "executable"
+	// LanguageArgs are the arguments passed to the executable
+	LanguageArgs properties.Property = "args"
+	// LanguageVersionRegex parses the output of the executable using named groups
+	LanguageVersionRegex properties.Property = "version_regex"
+	// LanguageProjectFiles enable the segment when found in the current or a parent folder
+	LanguageProjectFiles properties.Property = "project_files"
+	// LanguageVersionFile holds the version the project expects, searched in the current and parent folders
+	LanguageVersionFile properties.Property = "version_file"
+	// LanguageVersionFileRegex extracts the version from the version file using the version named group
+	LanguageVersionFileRegex properties.Property = "version_file_regex"
+
+	customLanguageVersionRegex = `(?P<version>(?P<major>[0-9]+)\.(?P<minor>[0-9]+)(?:\.(?P<patch>[0-9]+))?(?:-(?P<prerelease>[0-9A-Za-z.-]+))?(?:\+(?P<buildmetadata>[0-9A-Za-z.-]+))?)`
+)
+
+func (c *CustomLanguage) Template() string {
+	return languageTemplate
+}
+
+func (c *CustomLanguage) Enabled() bool {
+	executable := c.props.GetString(LanguageExecutable, "")
+	if len(executable) == 0 {
+		log.Error(errors.New("language segment: the executable property is required"))
+		return false
+	}
+
+	args := c.props.GetStringArray(LanguageArgs, []string{"--version"})
+
+	// every definition caches its own version
+	c.name = fmt.Sprintf("language %s %s", executable, strings.Join(args, " "))
+	c.projectFiles = c.props.GetStringArray(LanguageProjectFiles, []string{})
+	c.displayMode = c.props.GetString(DisplayMode, DisplayModeContext)
+	c.commands = []*cmd{
+		{
+			executable: executable,
+			args:       args,
+			regex:      c.props.GetString(LanguageVersionRegex, customLanguageVersionRegex),
+		},
+	}
+
+	if versionFile := c.props.GetString(LanguageVersionFile, ""); len(versionFile) != 0 {
+		c.Language.matchesVersionFile = c.matchesVersionFile
+		c.versionFile = versionFile
+	}
+
+	return c.Language.Enabled()
+}
+
+// matchesVersionFile compares the version to the one in the version file,
+// a partial version like 1.2 matches every 1.2.x version.
+func (c *CustomLanguage) matchesVersionFile() (string, bool) {
+	file, err := c.env.HasParentFilePath(c.props.GetString(LanguageVersionFile, ""), false)
+	if err != nil {
+		return "", true
+	}
+
+	content := c.env.FileContent(file.Path)
+
+	var expected string
+	if pattern := c.props.GetString(LanguageVersionFileRegex, ""); len(pattern) != 0 {
+		expected = regex.FindNamedRegexMatch(pattern, content)["version"]
+	} else {
+		expected = firstLine(content)
+	}
+
+	expected = strings.TrimPrefix(strings.TrimSpace(expected), "v")
+	if len(expected) == 0 {
+		return "", true
+	}
+
+	return expected, c.Full == expected || strings.HasPrefix(c.Full, expected+".")
+}
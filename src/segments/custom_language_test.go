@@ -0,0 +1,147 @@
+package segments
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomLanguage(t *testing.T) {
+	cases := []struct {
+		Case             string
+		Template         string
+		Regex            string
+		VersionFile      string
+		VersionFileRegex string
+		ExpectedString   string
+		Args             []string
+		NoExecutable     bool
+		ExpectedDisabled bool
+	}{
+		{Case: "No executable", NoExecutable: true, ExpectedDisabled: true},
+		{Case: "Default regex", ExpectedString: "4.2.1-beta"},
+		{Case: "Parts", Template: "{{ .Major }}/{{ .Minor }}/{{ .Patch }} {{ .Executable }}", ExpectedString: "4/2/1 forge"},
+		{Case: "Custom regex and args", Args: []string{"info", "--short"}, Regex: `build (?P<version>[a-z0-9]+)`, ExpectedString: "7f3a9c1"},
+		{Case: "Unparsable output", Regex: `forge (?P<version>[0-9]+)\.x`, ExpectedString: "err parsing info from forge with forge 4.2.1-beta (build 7f3a9c1)"},
+		{
+			Case:           "Version file match",
+			Template:       "{{ .Full }}{{ if .Mismatch }} ≠ {{ .Expected }}{{ end }}",
+			VersionFile:    "v4.2",
+			ExpectedString: "4.2.1-beta",
+		},
+		{
+			Case:           "Version file mismatch",
+			Template:       "{{ .Full }}{{ if .Mismatch }} ({{ .Reason }}){{ end }}",
+			VersionFile:    "4.3.0\n",
+			ExpectedString: "4.2.1-beta (.forge-version requires 4.3.0)",
+		},
+		{
+			Case:             "Version file regex",
+			Template:         "{{ .Full }}{{ if .Mismatch }} ≠ {{ .Expected }}{{ end }}",
+			VersionFile:      "[toolchain]\nforge = \"5.0\"\n",
+			VersionFileRegex: `forge = "(?P<version>[^"]+)"`,
+			ExpectedString:   "4.2.1-beta ≠ 5.0",
+		},
+	}
+
+	for _, tc := range cases {
+		args := tc.Args
+		if len(args) == 0 {
+			args = []string{"--version"}
+		}
+
+		params := &mockedLanguageParams{
+			cmd:           "forge",
+			versionOutput: "forge 4.2.1-beta (build 7f3a9c1)",
+			extension:     "*.forge",
+		}
+		env, props := getMockedLanguageEnv(params)
+		env.On("RunCommand", "forge", args).Return(params.versionOutput, nil)
+		env.On("HasParentFilePath", "forge.toml", false).Return(&runtime.FileInfo{}, errors.New("no match"))
+
+		if len(tc.VersionFile) != 0 {
+			env.On("HasParentFilePath", ".forge-version", false).Return(&runtime.FileInfo{Path: "/usr/home/.forge-version"}, nil)
+			env.On("FileContent", "/usr/home/.forge-version").Return(tc.VersionFile)
+			props[LanguageVersionFile] = ".forge-version"
+			props[LanguageVersionFileRegex] = tc.VersionFileRegex
+		}
+
+		if !tc.NoExecutable {
+			props[LanguageExecutable] = "forge"
+		}
+
+		props[LanguageExtensions] = []string{"*.forge"}
+		props[LanguageProjectFiles] = []string{"forge.toml"}
+
+		if len(tc.Args) != 0 {
+			props[LanguageArgs] = tc.Args
+		}
+
+		if len(tc.Regex) != 0 {
+			props[LanguageVersionRegex] = tc.Regex
+		}
+
+		c := &CustomLanguage{}
+		c.Init(props, env)
+
+		if tc.ExpectedDisabled {
+			assert.False(t, c.Enabled(), tc.Case)
+			continue
+		}
+
+		assert.True(t, c.Enabled(), tc.Case)
+
+		template := tc.Template
+		if len(template) == 0 {
+			template = c.Template()
+		}
+
+		assert.Equal(t, tc.ExpectedString, renderTemplate(env, template, c), tc.Case)
+	}
+}
+
+func TestCustomLanguageCacheKey(t *testing.T) {
+	env, props := getMockedLanguageEnv(&mockedLanguageParams{cmd: "forge", extension: "*.forge"})
+	env.On("RunCommand", "forge", []string{"--version"}).Return("forge 4.2.1", nil)
+	props[LanguageExecutable] = "forge"
+	props[LanguageExtensions] = []string{"*.forge"}
+	props[properties.FetchVersion] = true
+
+	c := &CustomLanguage{}
+	c.Init(props, env)
+
+	assert.True(t, c.Enabled())
+	assert.Equal(t, "language forge --version", c.name)
+}
+
+func TestCustomLanguageDisplayMode(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Mode     string
+		Expected string
+	}{
+		{Case: "Default", Expected: DisplayModeContext},
+		{Case: "Files", Mode: DisplayModeFiles, Expected: DisplayModeFiles},
+	}
+
+	for _, tc := range cases {
+		env, props := getMockedLanguageEnv(&mockedLanguageParams{cmd: "forge", extension: "*.forge"})
+		env.On("RunCommand", "forge", []string{"--version"}).Return("forge 4.2.1", nil)
+		props[LanguageExecutable] = "forge"
+		props[LanguageExtensions] = []string{"*.forge"}
+
+		if len(tc.Mode) != 0 {
+			props[DisplayMode] = tc.Mode
+		}
+
+		c := &CustomLanguage{}
+		c.Init(props, env)
+
+		assert.True(t, c.Enabled(), tc.Case)
+		assert.Equal(t, tc.Expected, c.displayMode, tc.Case)
+	}
+}
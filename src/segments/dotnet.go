@@ -9,7 +9,8 @@ import (
 
 type globalJSON struct {
 	Sdk struct {
-		Version string `json:"version"`
+		Version     string `json:"version"`
+		RollForward string `json:"rollForward"`
 	} `json:"sdk"`
 }
 
@@ -51,6 +52,9 @@ func (d *Dotnet) Enabled() bool {
 				`(?:-(?P<prerelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+(?P<buildmetadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?))`,
 		},
 	}
+	d.constraints = []*versionConstraint{
+		{file: "global.json", source: "sdk.version", parse: globalJSONConstraint},
+	}
 	d.versionURLTemplate = "https://github.com/dotnet/core/blob/master/release-notes/{{ .Major }}.{{ .Minor }}/{{ .Major }}.{{ .Minor }}.{{ substr 0 1 .Patch }}/{{ .Major }}.{{ .Minor }}.{{ substr 0 1 .Patch }}.md" //nolint: lll
 
 	enabled := d.Language.Enabled()
@@ -37,6 +37,8 @@ func TestDotnetSegment(t *testing.T) {
 			env.On("RunCommand", "dotnet", []string{"--version"}).Return("", err)
 		}
 
+		env.On("HasParentFilePath", "global.json", false).Return(&runtime.FileInfo{}, errors.New("no match"))
+
 		dotnet := &Dotnet{}
 		dotnet.Init(props, env)
 
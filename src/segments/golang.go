@@ -33,6 +33,20 @@ func (g *Golang) Enabled() bool {
 		},
 	}
 	g.versionURLTemplate = "https://golang.org/doc/go{{ .Major }}.{{ .Minor }}"
+	g.constraints = []*versionConstraint{
+		{file: "go.mod", source: "go directive", parse: goDirectiveConstraint},
+	}
+
+	// the version in go.mod or go.work is requested explicitly
+	if !g.props.GetBool(ParseModFile, false) && !g.props.GetBool(ParseWorkFile, false) {
+		g.toolchain = &toolchain{
+			tools:    []string{"golang", "go"},
+			env:      []string{"GOENV_VERSION"},
+			files:    []string{".go-version"},
+			globals:  []string{"$GOENV_ROOT/version", "~/.goenv/version"},
+			installs: []string{"$GOENV_ROOT/versions", "~/.goenv/versions"},
+		}
+	}
 
 	return g.Language.Enabled()
 }
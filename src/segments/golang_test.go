@@ -118,6 +118,8 @@ func TestGolang(t *testing.T) {
 			env.On("FileContent", fileInfo.Path).Return(content)
 		}
 
+		mockNoToolchain(env)
+
 		g := &Golang{}
 		g.Init(props, env)
 
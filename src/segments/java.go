@@ -42,6 +42,13 @@ func (j *Java) init() {
 		"*.cljc",
 	}
 
+	j.toolchain = &toolchain{
+		tools:    []string{"java"},
+		active:   []string{"JAVA_HOME"},
+		files:    []string{".sdkmanrc", ".java-version"},
+		installs: []string{"$SDKMAN_DIR/candidates/java", "~/.sdkman/candidates/java"},
+	}
+
 	javaHome := j.env.Getenv("JAVA_HOME")
 	if len(javaHome) > 0 {
 		java := fmt.Sprintf("%s/bin/java", javaHome)
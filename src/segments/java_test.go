@@ -69,6 +69,8 @@ func TestJava(t *testing.T) {
 			env.On("Getenv", "JAVA_HOME").Return("")
 		}
 
+		mockNoToolchain(env)
+
 		j := &Java{}
 		j.Init(props, env)
 		assert.True(t, j.Enabled(), fmt.Sprintf("Failed in case: %s", tc.Case))
@@ -2,8 +2,14 @@ package segments
 
 import (
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/regex"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,14 +18,26 @@ import (
 const (
 	ParseKubeConfig properties.Property = "parse_kubeconfig"
 	ContextAliases  properties.Property = "context_aliases"
+	// Environments maps context or cluster names to an environment
+	Environments properties.Property = "environments"
+	// EnvironmentPatterns maps environments to a regex matching the context name, cluster name or server URL
+	EnvironmentPatterns properties.Property = "environment_patterns"
 )
 
 type Kubectl struct {
 	Base
 
+	TokenExpiration time.Time
 	KubeContext
-	Context string
-	dirty   bool
+	Context      string
+	Server       string
+	AuthProvider string
+	Environment  string
+	// Conflicts lists the entries defined differently in several KUBECONFIG files, like context dev
+	Conflicts     []string
+	TokenTimeLeft time.Duration
+	TokenExpired  bool
+	dirty         bool
 }
 
 type KubeConfig struct {
@@ -28,6 +46,14 @@ type KubeConfig struct {
 		Context *KubeContext `yaml:"context"`
 		Name    string       `yaml:"name"`
 	} `yaml:"contexts"`
+	Clusters []struct {
+		Cluster *KubeCluster `yaml:"cluster"`
+		Name    string       `yaml:"name"`
+	} `yaml:"clusters"`
+	Users []struct {
+		User *KubeUser `yaml:"user"`
+		Name string    `yaml:"name"`
+	} `yaml:"users"`
 }
 
 type KubeContext struct {
@@ -36,6 +62,50 @@ type KubeContext struct {
 	Namespace string `yaml:"namespace"`
 }
 
+type KubeCluster struct {
+	Server string `yaml:"server"`
+}
+
+type KubeUser struct {
+	Exec *struct {
+		Command string   `yaml:"command"`
+		Args    []string `yaml:"args"`
+	} `yaml:"exec"`
+	AuthProvider *struct {
+		Name string `yaml:"name"`
+	} `yaml:"auth-provider"`
+	Token                 string `yaml:"token"`
+	TokenFile             string `yaml:"tokenFile"`
+	ClientCertificate     string `yaml:"client-certificate"`
+	ClientCertificateData string `yaml:"client-certificate-data"`
+	Username              string `yaml:"username"`
+}
+
+// kubeEntries merges the entries of the KUBECONFIG files, the first file defining an entry wins.
+type kubeEntries[T any] struct {
+	values    map[string]*T
+	conflicts map[string]bool
+}
+
+func newKubeEntries[T any]() *kubeEntries[T] {
+	return &kubeEntries[T]{
+		values:    make(map[string]*T),
+		conflicts: make(map[string]bool),
+	}
+}
+
+func (e *kubeEntries[T]) add(name string, value *T) {
+	existing, exists := e.values[name]
+	if !exists {
+		e.values[name] = value
+		return
+	}
+
+	if !reflect.DeepEqual(existing, value) {
+		e.conflicts[name] = true
+	}
+}
+
 func (k *Kubectl) Template() string {
 	return " {{ .Context }}{{ if .Namespace }} :: {{ .Namespace }}{{ end }} "
 }
@@ -58,7 +128,9 @@ func (k *Kubectl) doParseKubeConfig() bool {
 		kubeconfigs = []string{filepath.Join(k.env.Home(), ".kube/config")}
 	}
 
-	contexts := make(map[string]*KubeContext)
+	contexts := newKubeEntries[KubeContext]()
+	clusters := newKubeEntries[KubeCluster]()
+	users := newKubeEntries[KubeUser]()
 	k.Context = ""
 
 	for _, kubeconfig := range kubeconfigs {
@@ -75,38 +147,60 @@ func (k *Kubectl) doParseKubeConfig() bool {
 		}
 
 		for _, context := range config.Contexts {
-			if _, exists := contexts[context.Name]; !exists {
-				contexts[context.Name] = context.Context
-			}
+			contexts.add(context.Name, context.Context)
 		}
 
-		if k.Context == "" {
-			k.Context = config.CurrentContext
+		for _, cluster := range config.Clusters {
+			clusters.add(cluster.Name, cluster.Cluster)
 		}
 
-		context, exists := contexts[k.Context]
-		if !exists {
+		for _, user := range config.Users {
+			users.add(user.Name, user.User)
+		}
+
+		if k.Context == "" {
+			k.Context = config.CurrentContext
 			continue
 		}
 
-		if context != nil {
-			k.KubeContext = *context
+		if len(config.CurrentContext) != 0 && config.CurrentContext != k.Context && !slices.Contains(k.Conflicts, "current-context") {
+			k.Conflicts = append(k.Conflicts, "current-context")
 		}
+	}
 
-		k.SetContextAlias()
-		k.dirty = true
+	context, exists := contexts.values[k.Context]
+	if !exists {
+		displayError := k.props.GetBool(properties.DisplayError, false)
+		if !displayError {
+			return false
+		}
 
+		k.setError("KUBECONFIG ERR")
 		return true
 	}
 
-	displayError := k.props.GetBool(properties.DisplayError, false)
-	if !displayError {
-		return false
+	if context != nil {
+		k.KubeContext = *context
 	}
-	k.setError("KUBECONFIG ERR")
+
+	k.addConflicts("context", contexts.conflicts, k.Context)
+	k.addConflicts("cluster", clusters.conflicts, k.Cluster)
+	k.addConflicts("user", users.conflicts, k.User)
+
+	k.setClusterInfo(clusters.values[k.Cluster], users.values[k.User])
+	k.SetContextAlias()
+	k.dirty = true
+
 	return true
 }
 
+// addConflicts reports the conflicting entries used by the current context.
+func (k *Kubectl) addConflicts(kind string, conflicts map[string]bool, name string) {
+	if conflicts[name] {
+		k.Conflicts = append(k.Conflicts, kind+" "+name)
+	}
+}
+
 func (k *Kubectl) doCallKubectl() bool {
 	cmd := "kubectl"
 	if !k.env.HasCommand(cmd) {
@@ -131,16 +225,93 @@ func (k *Kubectl) doCallKubectl() bool {
 	}
 
 	k.Context = config.CurrentContext
-	k.SetContextAlias()
-	k.dirty = true
 
 	if len(config.Contexts) > 0 {
 		k.KubeContext = *config.Contexts[0].Context
 	}
 
+	// --minify only keeps the cluster and user of the current context
+	var cluster *KubeCluster
+	if len(config.Clusters) > 0 {
+		cluster = config.Clusters[0].Cluster
+	}
+
+	var user *KubeUser
+	if len(config.Users) > 0 {
+		user = config.Users[0].User
+	}
+
+	k.setClusterInfo(cluster, user)
+	k.SetContextAlias()
+	k.dirty = true
+
 	return true
 }
 
+func (k *Kubectl) setClusterInfo(cluster *KubeCluster, user *KubeUser) {
+	if cluster != nil {
+		k.Server = cluster.Server
+	}
+
+	if user != nil {
+		k.AuthProvider = user.provider()
+		k.setTokenExpiration(user)
+	}
+
+	k.Environment = k.classify()
+}
+
+// classify returns the environment of the context, before applying the context alias.
+// Explicit environments take precedence over the patterns, which are evaluated in alphabetical order.
+func (k *Kubectl) classify() string {
+	environments := k.props.GetKeyValueMap(Environments, map[string]string{})
+
+	for _, name := range []string{k.Context, k.Cluster} {
+		if environment, exists := environments[name]; exists && len(name) != 0 {
+			return environment
+		}
+	}
+
+	patterns := k.props.GetKeyValueMap(EnvironmentPatterns, map[string]string{})
+
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		matches := func(value string) bool {
+			return len(value) != 0 && regex.MatchString(patterns[name], value)
+		}
+
+		if slices.ContainsFunc([]string{k.Context, k.Cluster, k.Server}, matches) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// provider names how the user authenticates: the exec plugin, the auth provider, token, certificate or basic.
+func (u *KubeUser) provider() string {
+	switch {
+	case u.Exec != nil:
+		return u.execCommand()
+	case u.AuthProvider != nil:
+		return u.AuthProvider.Name
+	case len(u.Token) != 0 || len(u.TokenFile) != 0:
+		return "token"
+	case len(u.ClientCertificate) != 0 || len(u.ClientCertificateData) != 0:
+		return "certificate"
+	case len(u.Username) != 0:
+		return "basic"
+	default:
+		return ""
+	}
+}
+
 func (k *Kubectl) setError(message string) {
 	if k.Context == "" {
 		k.Context = message
@@ -157,3 +328,7 @@ func (k *Kubectl) SetContextAlias() {
 		k.Context = alias
 	}
 }
+
+func (u *KubeUser) execCommand() string {
+	return strings.TrimSuffix(filepath.Base(u.Exec.Command), ".exe")
+}
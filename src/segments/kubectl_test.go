@@ -1,10 +1,14 @@
 package segments
 
 import (
+	"encoding/base64"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
@@ -174,6 +178,211 @@ func TestKubectlSegment(t *testing.T) {
 	}
 }
 
+func TestKubectlClusterInfo(t *testing.T) {
+	kubeconfig := `
+apiVersion: v1
+current-context: prod-eu
+contexts:
+  - name: prod-eu
+    context:
+      cluster: eks-prod
+      user: aws
+  - name: sandbox
+    context:
+      cluster: kind
+      user: admin
+  - name: gke
+    context:
+      cluster: gke-cluster
+      user: gke
+clusters:
+  - name: eks-prod
+    cluster:
+      server: https://ABC.gr7.eu-west-1.eks.amazonaws.com
+  - name: kind
+    cluster:
+      server: https://127.0.0.1:6443
+users:
+  - name: aws
+    user:
+      exec:
+        command: aws
+        args: [eks, get-token, --cluster-name, prod]
+  - name: admin
+    user:
+      client-certificate-data: ZGF0YQ==
+  - name: gke
+    user:
+      auth-provider:
+        name: gcp
+`
+
+	override := `
+apiVersion: v1
+current-context: sandbox
+clusters:
+  - name: kind
+    cluster:
+      server: https://127.0.0.1:7443
+`
+
+	cases := []struct {
+		Environments         map[string]string
+		EnvironmentPatterns  map[string]string
+		Case                 string
+		Kubeconfig           string
+		ExpectedServer       string
+		ExpectedAuthProvider string
+		ExpectedEnvironment  string
+		ExpectedConflicts    []string
+	}{
+		{
+			Case:                 "Exec plugin",
+			Kubeconfig:           "main",
+			ExpectedServer:       "https://ABC.gr7.eu-west-1.eks.amazonaws.com",
+			ExpectedAuthProvider: "aws",
+		},
+		{
+			Case:                 "Pattern on the context",
+			Kubeconfig:           "main",
+			EnvironmentPatterns:  map[string]string{"prod": "^prod-", "dev": "^dev-"},
+			ExpectedServer:       "https://ABC.gr7.eu-west-1.eks.amazonaws.com",
+			ExpectedAuthProvider: "aws",
+			ExpectedEnvironment:  "prod",
+		},
+		{
+			Case:                 "Environment by cluster name",
+			Kubeconfig:           "main",
+			Environments:         map[string]string{"eks-prod": "production"},
+			EnvironmentPatterns:  map[string]string{"prod": "^prod-"},
+			ExpectedServer:       "https://ABC.gr7.eu-west-1.eks.amazonaws.com",
+			ExpectedAuthProvider: "aws",
+			ExpectedEnvironment:  "production",
+		},
+		{
+			Case:                 "Conflicting files",
+			Kubeconfig:           "override" + string(filepath.ListSeparator) + "main",
+			EnvironmentPatterns:  map[string]string{"dev": `127\.0\.0\.1`},
+			ExpectedServer:       "https://127.0.0.1:7443",
+			ExpectedAuthProvider: "certificate",
+			ExpectedEnvironment:  "dev",
+			ExpectedConflicts:    []string{"current-context", "cluster kind"},
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Getenv", "KUBECONFIG").Return(tc.Kubeconfig)
+		env.On("FileContent", "main").Return(kubeconfig)
+		env.On("FileContent", "override").Return(override)
+
+		props := properties.Map{
+			Environments:        tc.Environments,
+			EnvironmentPatterns: tc.EnvironmentPatterns,
+		}
+
+		k := &Kubectl{}
+		k.Init(props, env)
+
+		assert.True(t, k.Enabled(), tc.Case)
+		assert.Equal(t, tc.ExpectedServer, k.Server, tc.Case)
+		assert.Equal(t, tc.ExpectedAuthProvider, k.AuthProvider, tc.Case)
+		assert.Equal(t, tc.ExpectedEnvironment, k.Environment, tc.Case)
+		assert.Equal(t, tc.ExpectedConflicts, k.Conflicts, tc.Case)
+	}
+}
+
+func TestKubectlTokenExpiration(t *testing.T) {
+	inAnHour := time.Now().Add(time.Hour)
+
+	idToken := func(issuer string, expiry time.Time) string {
+		payload := fmt.Sprintf(`{"iss":"%s","exp":%d}`, issuer, expiry.Unix())
+		return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+	}
+
+	cases := []struct {
+		Case            string
+		Command         string
+		Args            string
+		GKECache        string
+		ExpectedExpired bool
+		ExpectedTimeSet bool
+	}{
+		{Case: "No cache", Command: "aws"},
+		{
+			Case:            "GKE",
+			Command:         "gke-gcloud-auth-plugin",
+			GKECache:        `{"current_context":"ctx","access_token":"token","token_expiry":"2020-01-01T10:00:00Z"}`,
+			ExpectedTimeSet: true,
+			ExpectedExpired: true,
+		},
+		{
+			Case:     "GKE other context",
+			Command:  "gke-gcloud-auth-plugin",
+			GKECache: `{"current_context":"other","access_token":"token","token_expiry":"2020-01-01T10:00:00Z"}`,
+		},
+		{
+			Case:            "OIDC login",
+			Command:         "kubectl",
+			Args:            "[oidc-login, get-token, --oidc-issuer-url=https://issuer.posh.dev, --oidc-client-id=posh]",
+			ExpectedTimeSet: true,
+		},
+		{
+			Case:    "OIDC login other issuer",
+			Command: "kubectl",
+			Args:    "[oidc-login, get-token, --oidc-issuer-url, https://other.posh.dev]",
+		},
+	}
+
+	for _, tc := range cases {
+		kubeconfig := fmt.Sprintf(`
+apiVersion: v1
+current-context: ctx
+contexts:
+  - name: ctx
+    context:
+      cluster: cl
+      user: usr
+users:
+  - name: usr
+    user:
+      exec:
+        command: %s
+        args: %s
+`, tc.Command, tc.Args)
+
+		if len(tc.Args) == 0 {
+			kubeconfig = strings.Replace(kubeconfig, "args: ", "args: []", 1)
+		}
+
+		cacheDir := filepath.Join("home", ".kube", "cache", "oidc-login")
+
+		env := new(mock.Environment)
+		env.On("Getenv", "KUBECONFIG").Return("")
+		env.On("Home").Return("home")
+		env.On("FileContent", filepath.Join("home", ".kube/config")).Return(kubeconfig)
+		env.On("FileContent", filepath.Join("home", ".kube", "gke_gcloud_auth_plugin_cache")).Return(tc.GKECache)
+		env.On("LsDir", cacheDir).Return([]fs.DirEntry{
+			&MockDirEntry{name: "1d7c2b"},
+			&MockDirEntry{name: "9f8e7d"},
+		})
+		env.On("FileContent", filepath.Join(cacheDir, "1d7c2b")).Return(`{"id_token":"` + idToken("https://issuer.posh.dev/", inAnHour) + `"}`)
+		env.On("FileContent", filepath.Join(cacheDir, "9f8e7d")).Return(`{"id_token":"` + idToken("https://issuer.posh.dev", inAnHour.Add(-2*time.Hour)) + `"}`)
+
+		k := &Kubectl{}
+		k.Init(properties.Map{}, env)
+
+		assert.True(t, k.Enabled(), tc.Case)
+		assert.Equal(t, tc.ExpectedTimeSet, !k.TokenExpiration.IsZero(), tc.Case)
+		assert.Equal(t, tc.ExpectedExpired, k.TokenExpired, tc.Case)
+
+		if tc.ExpectedTimeSet && !tc.ExpectedExpired {
+			assert.Equal(t, inAnHour.Unix(), k.TokenExpiration.Unix(), tc.Case)
+			assert.InDelta(t, time.Hour, k.TokenTimeLeft, float64(time.Minute), tc.Case)
+		}
+	}
+}
+
 var testKubeConfigFiles = map[string]string{
 	filepath.Join("testhome", ".kube/config"): `
 apiVersion: v1
@@ -0,0 +1,145 @@
+package segments
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+)
+
+// setTokenExpiration reads the token cached by the exec plugin of the user, when the plugin caches it on disk.
+func (k *Kubectl) setTokenExpiration(user *KubeUser) {
+	if user.Exec == nil {
+		return
+	}
+
+	var expiration time.Time
+
+	switch command := user.execCommand(); {
+	case command == "gke-gcloud-auth-plugin":
+		expiration = k.gkeTokenExpiration()
+	case command == "kubectl-oidc_login", command == "kubectl" && slices.Contains(user.Exec.Args, "oidc-login"):
+		expiration = k.oidcTokenExpiration(user.Exec.Args)
+	}
+
+	if expiration.IsZero() {
+		return
+	}
+
+	k.TokenExpiration = expiration
+	k.TokenTimeLeft = max(time.Until(expiration), 0)
+	k.TokenExpired = k.TokenTimeLeft == 0
+}
+
+// gkeTokenExpiration reads the token gke-gcloud-auth-plugin caches for the current context.
+func (k *Kubectl) gkeTokenExpiration() time.Time {
+	content := k.env.FileContent(filepath.Join(k.env.Home(), ".kube", "gke_gcloud_auth_plugin_cache"))
+	if len(content) == 0 {
+		return time.Time{}
+	}
+
+	var cache struct {
+		CurrentContext string    `json:"current_context"`
+		TokenExpiry    time.Time `json:"token_expiry"`
+	}
+
+	if err := json.Unmarshal([]byte(content), &cache); err != nil {
+		log.Error(err)
+		return time.Time{}
+	}
+
+	// the plugin only caches the token of the last context it was called for
+	if cache.CurrentContext != k.Context {
+		return time.Time{}
+	}
+
+	return cache.TokenExpiry
+}
+
+// oidcTokenExpiration reads the ID tokens kubelogin caches, and returns the expiry of the latest one
+// issued by the issuer of the user. The cache files are named by a hash of the settings, so the issuer
+// in the token is used to find them.
+func (k *Kubectl) oidcTokenExpiration(args []string) time.Time {
+	issuer := execArg(args, "--oidc-issuer-url")
+	if len(issuer) == 0 {
+		return time.Time{}
+	}
+
+	dir := execArg(args, "--token-cache-dir")
+	if len(dir) == 0 {
+		dir = filepath.Join(k.env.Home(), ".kube", "cache", "oidc-login")
+	}
+
+	dir = strings.Replace(dir, "~", k.env.Home(), 1)
+
+	var expiration time.Time
+
+	for _, entry := range k.env.LsDir(dir) {
+		if entry.IsDir() {
+			continue
+		}
+
+		var cache struct {
+			IDToken string `json:"id_token"`
+		}
+
+		if err := json.Unmarshal([]byte(k.env.FileContent(filepath.Join(dir, entry.Name()))), &cache); err != nil {
+			continue
+		}
+
+		claims, ok := jwtClaims(cache.IDToken)
+		if !ok || strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+			continue
+		}
+
+		if exp := time.Unix(claims.Expiry, 0); exp.After(expiration) {
+			expiration = exp
+		}
+	}
+
+	return expiration
+}
+
+type jwtPayload struct {
+	Issuer string `json:"iss"`
+	Expiry int64  `json:"exp"`
+}
+
+// jwtClaims decodes the payload of a JWT without verifying it, the prompt only needs the expiry.
+func jwtClaims(token string) (*jwtPayload, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+
+	var claims jwtPayload
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Expiry == 0 {
+		return nil, false
+	}
+
+	return &claims, true
+}
+
+// execArg returns the value of a flag passed to an exec plugin, as --flag=value or --flag value.
+func execArg(args []string, flag string) string {
+	for i, arg := range args {
+		if value, found := strings.CutPrefix(arg, flag+"="); found {
+			return value
+		}
+
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
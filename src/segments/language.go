@@ -73,12 +73,16 @@ type Language struct {
 	loadContext        loadContext
 	inContext          inContext
 	matchesVersionFile matchesVersionFile
+	toolchain          *toolchain
+	Reason             string
 	Version
 	displayMode        string
 	Error              string
 	versionURLTemplate string
+	versionFile        string
 	name               string
 	commands           []*cmd
+	constraints        []*versionConstraint
 	projectFiles       []string
 	folders            []string
 	extensions         []string
@@ -115,7 +119,10 @@ func (l *Language) getName() string {
 }
 
 func (l *Language) Enabled() bool {
-	l.name = l.getName()
+	if len(l.name) == 0 {
+		l.name = l.getName()
+	}
+
 	// override default extensions if needed
 	l.extensions = l.props.GetStringArray(LanguageExtensions, l.extensions)
 	l.folders = l.props.GetStringArray(LanguageFolders, l.folders)
@@ -170,9 +177,14 @@ func (l *Language) Enabled() bool {
 		if !match {
 			l.Mismatch = true
 			l.Expected = expected
+			l.Reason = fmt.Sprintf("%s requires %s", l.versionFile, expected)
 		}
 	}
 
+	if !l.Mismatch {
+		l.checkVersionConstraints()
+	}
+
 	return enabled
 }
 
@@ -201,6 +213,15 @@ func (l *Language) setVersion() error {
 
 	cacheKey := fmt.Sprintf("version_%s", l.name)
 
+	if toolchain, OK := l.resolveToolchain(); OK {
+		if l.setToolchainVersion(toolchain) {
+			return nil
+		}
+
+		// the folder doesn't tell the version, cache the binary's version per toolchain
+		cacheKey = fmt.Sprintf("version_%s_%s", l.name, toolchain.path)
+	}
+
 	if versionCache, OK := cache.Get[Version](cache.Device, cacheKey); OK {
 		l.Version = versionCache
 		return nil
@@ -11,8 +11,15 @@ import (
 type Node struct {
 	PackageManagerIcon string
 	PackageManagerName string
+	// PackageManagerVersion is the version declared in the packageManager field of package.json
+	PackageManagerVersion string
+	LockFiles             []string
 
 	Language
+
+	Corepack            bool
+	MixedLockFiles      bool
+	NodeModulesOutdated bool
 }
 
 const (
@@ -24,7 +31,9 @@ const (
 	NPMIcon properties.Property = "npm_icon"
 	// BunIcon illustrates Bun is used
 	BunIcon properties.Property = "bun_icon"
-	// FetchPackageManager shows if Bun, NPM, PNPM, or Yarn is used
+	// DenoIcon illustrates Deno is used
+	DenoIcon properties.Property = "deno_icon"
+	// FetchPackageManager shows if Bun, Deno, NPM, PNPM, or Yarn is used
 	FetchPackageManager properties.Property = "fetch_package_manager"
 )
 
@@ -42,70 +51,23 @@ func (n *Node) Enabled() bool {
 		},
 	}
 	n.versionURLTemplate = "https://github.com/nodejs/node/blob/master/doc/changelogs/CHANGELOG_V{{ .Major }}.md#{{ .Full }}"
+	n.toolchain = &toolchain{
+		tools:    []string{"nodejs", "node"},
+		active:   []string{"NVM_BIN"},
+		files:    []string{".nvmrc", ".node-version"},
+		globals:  []string{"$NVM_DIR/alias/default", "~/.nvm/alias/default"},
+		installs: []string{"$NVM_DIR/versions/node", "~/.nvm/versions/node"},
+	}
 	n.Language.matchesVersionFile = n.matchesVersionFile
+	n.versionFile = ".nvmrc"
+	n.constraints = []*versionConstraint{
+		{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+	}
 	n.Language.loadContext = n.loadContext
 
 	return n.Language.Enabled()
 }
 
-func (n *Node) loadContext() {
-	if !n.props.GetBool(FetchPackageManager, false) {
-		return
-	}
-
-	packageManagerDefinitions := []struct {
-		fileName     string
-		name         string
-		iconProperty properties.Property
-		defaultIcon  string
-	}{
-		{
-			fileName:     "pnpm-lock.yaml",
-			name:         "pnpm",
-			iconProperty: PnpmIcon,
-			defaultIcon:  "\U000F02C1",
-		},
-		{
-			fileName:     "yarn.lock",
-			name:         "yarn",
-			iconProperty: YarnIcon,
-			defaultIcon:  "\U000F011B",
-		},
-		{
-			fileName:     "bun.lockb",
-			name:         "bun",
-			iconProperty: BunIcon,
-			defaultIcon:  "\ue76f",
-		},
-		{
-			fileName:     "bun.lock",
-			name:         "bun",
-			iconProperty: BunIcon,
-			defaultIcon:  "\ue76f",
-		},
-		{
-			fileName:     "package-lock.json",
-			name:         "npm",
-			iconProperty: NPMIcon,
-			defaultIcon:  "\uE71E",
-		},
-		{
-			fileName:     "package.json",
-			name:         "npm",
-			iconProperty: NPMIcon,
-			defaultIcon:  "\uE71E",
-		},
-	}
-
-	for _, pm := range packageManagerDefinitions {
-		if n.env.HasFiles(pm.fileName) {
-			n.PackageManagerName = pm.name
-			n.PackageManagerIcon = n.props.GetString(pm.iconProperty, pm.defaultIcon)
-			break
-		}
-	}
-}
-
 func (n *Node) matchesVersionFile() (string, bool) {
 	fileVersion := n.env.FileContent(".nvmrc")
 	if fileVersion == "" {
@@ -0,0 +1,183 @@
+package segments
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+)
+
+type nodePackageManager struct {
+	name         string
+	iconProperty properties.Property
+	defaultIcon  string
+	lockFiles    []string
+}
+
+// nodePackageManagers are ordered by precedence when no package manager is declared in package.json.
+var nodePackageManagers = []*nodePackageManager{
+	{
+		name:         "pnpm",
+		lockFiles:    []string{"pnpm-lock.yaml"},
+		iconProperty: PnpmIcon,
+		defaultIcon:  "\U000F02C1",
+	},
+	{
+		name:         "yarn",
+		lockFiles:    []string{"yarn.lock"},
+		iconProperty: YarnIcon,
+		defaultIcon:  "\U000F011B",
+	},
+	{
+		name:         "bun",
+		lockFiles:    []string{"bun.lockb", "bun.lock"},
+		iconProperty: BunIcon,
+		defaultIcon:  "\ue76f",
+	},
+	{
+		name:         "deno",
+		lockFiles:    []string{"deno.lock"},
+		iconProperty: DenoIcon,
+		defaultIcon:  "\ue7c0",
+	},
+	{
+		name:         "npm",
+		lockFiles:    []string{"package-lock.json", "npm-shrinkwrap.json"},
+		iconProperty: NPMIcon,
+		defaultIcon:  "\uE71E",
+	},
+}
+
+// nodeModulesState are the files package managers write to node_modules on install.
+var nodeModulesState = []string{".package-lock.json", ".modules.yaml", ".yarn-state.yml", ".yarn-integrity"}
+
+func (n *Node) loadContext() {
+	if !n.props.GetBool(FetchPackageManager, false) {
+		return
+	}
+
+	n.setCorepack()
+
+	var managers []string
+
+	for _, pm := range nodePackageManagers {
+		for _, lockFile := range pm.lockFiles {
+			if !n.env.HasFiles(lockFile) {
+				continue
+			}
+
+			n.LockFiles = append(n.LockFiles, lockFile)
+
+			if !slices.Contains(managers, pm.name) {
+				managers = append(managers, pm.name)
+			}
+		}
+	}
+
+	switch {
+	case len(n.PackageManagerName) != 0:
+	case len(managers) != 0:
+		n.PackageManagerName = managers[0]
+	case n.env.HasFiles("package.json"):
+		n.PackageManagerName = "npm"
+	}
+
+	// lock files of another package manager than the one in use are left behind, or out of sync
+	n.MixedLockFiles = len(managers) > 1 || (len(managers) == 1 && managers[0] != n.PackageManagerName)
+
+	for _, pm := range nodePackageManagers {
+		if pm.name == n.PackageManagerName {
+			n.PackageManagerIcon = n.props.GetString(pm.iconProperty, pm.defaultIcon)
+			break
+		}
+	}
+
+	n.setNodeModulesOutdated()
+}
+
+// setCorepack reads the packageManager field of package.json, like pnpm@9.1.0+sha512.3a5f, which corepack uses.
+func (n *Node) setCorepack() {
+	if !n.env.HasFiles("package.json") {
+		return
+	}
+
+	var data struct {
+		PackageManager string `json:"packageManager"`
+	}
+
+	if err := json.Unmarshal([]byte(n.env.FileContent("package.json")), &data); err != nil {
+		log.Error(err)
+		return
+	}
+
+	name, version, found := strings.Cut(data.PackageManager, "@")
+	if !found || len(name) == 0 {
+		return
+	}
+
+	version, _, _ = strings.Cut(version, "+")
+
+	n.PackageManagerName = name
+	n.PackageManagerVersion = version
+	n.Corepack = true
+}
+
+// setNodeModulesOutdated checks if a lock file changed after the last install in node_modules.
+func (n *Node) setNodeModulesOutdated() {
+	if len(n.LockFiles) == 0 {
+		return
+	}
+
+	var lockTime, installTime time.Time
+
+	var hasNodeModules bool
+
+	for _, entry := range n.env.LsDir(n.env.Pwd()) {
+		name := entry.Name()
+		if entry.IsDir() && name != "node_modules" || !entry.IsDir() && !slices.Contains(n.LockFiles, name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if entry.IsDir() {
+			hasNodeModules = true
+			installTime = info.ModTime()
+			continue
+		}
+
+		if info.ModTime().After(lockTime) {
+			lockTime = info.ModTime()
+		}
+	}
+
+	if !hasNodeModules || lockTime.IsZero() {
+		return
+	}
+
+	// the folder itself only changes when packages are added or removed, the state files on every install
+	var stateTime time.Time
+
+	for _, entry := range n.env.LsDir(filepath.Join(n.env.Pwd(), "node_modules")) {
+		if entry.IsDir() || !slices.Contains(nodeModulesState, entry.Name()) {
+			continue
+		}
+
+		if info, err := entry.Info(); err == nil && info.ModTime().After(stateTime) {
+			stateTime = info.ModTime()
+		}
+	}
+
+	if !stateTime.IsZero() {
+		installTime = stateTime
+	}
+
+	n.NodeModulesOutdated = lockTime.After(installTime)
+}
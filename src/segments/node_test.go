@@ -1,7 +1,10 @@
 package segments
 
 import (
+	"io/fs"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
@@ -85,6 +88,11 @@ func TestNodeInContext(t *testing.T) {
 		env.On("HasFiles", "package.json").Return(tc.hasDefault)
 		env.On("HasFiles", "bun.lockb").Return(tc.hasBun)
 		env.On("HasFiles", "bun.lock").Return(tc.hasBun)
+		env.On("HasFiles", "deno.lock").Return(false)
+		env.On("HasFiles", "npm-shrinkwrap.json").Return(false)
+		env.On("FileContent", "package.json").Return("{}")
+		env.On("Pwd").Return("/posh")
+		env.On("LsDir", "/posh").Return([]fs.DirEntry{})
 
 		props := properties.Map{
 			PnpmIcon:            "pnpm",
@@ -101,3 +109,163 @@ func TestNodeInContext(t *testing.T) {
 		assert.Equal(t, tc.ExpectedString, node.PackageManagerIcon, tc.Case)
 	}
 }
+
+func TestNodePackageManager(t *testing.T) {
+	installed := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	changed := installed.Add(time.Hour)
+
+	cases := []struct {
+		Files             map[string]time.Time
+		NodeModules       map[string]time.Time
+		Case              string
+		PackageJSON       string
+		ExpectedName      string
+		ExpectedIcon      string
+		ExpectedVersion   string
+		ExpectedLockFiles []string
+		ExpectedCorepack  bool
+		ExpectedMixed     bool
+		ExpectedOutdated  bool
+	}{
+		{
+			Case:              "deno",
+			Files:             map[string]time.Time{"deno.lock": installed},
+			ExpectedName:      "deno",
+			ExpectedIcon:      "deno",
+			ExpectedLockFiles: []string{"deno.lock"},
+		},
+		{
+			Case:              "corepack",
+			PackageJSON:       `{"packageManager": "pnpm@9.1.0+sha512.3a5f9e1b"}`,
+			Files:             map[string]time.Time{"package.json": installed, "pnpm-lock.yaml": installed},
+			ExpectedName:      "pnpm",
+			ExpectedIcon:      "pnpm",
+			ExpectedVersion:   "9.1.0",
+			ExpectedLockFiles: []string{"pnpm-lock.yaml"},
+			ExpectedCorepack:  true,
+		},
+		{
+			Case:             "corepack without lock file",
+			PackageJSON:      `{"packageManager": "yarn@4.1.1"}`,
+			Files:            map[string]time.Time{"package.json": installed},
+			ExpectedName:     "yarn",
+			ExpectedIcon:     "yarn",
+			ExpectedVersion:  "4.1.1",
+			ExpectedCorepack: true,
+		},
+		{
+			Case:              "corepack with the lock file of another package manager",
+			PackageJSON:       `{"packageManager": "pnpm@9.1.0"}`,
+			Files:             map[string]time.Time{"package.json": installed, "package-lock.json": installed},
+			ExpectedName:      "pnpm",
+			ExpectedIcon:      "pnpm",
+			ExpectedVersion:   "9.1.0",
+			ExpectedLockFiles: []string{"package-lock.json"},
+			ExpectedCorepack:  true,
+			ExpectedMixed:     true,
+		},
+		{
+			Case:              "mixed lock files",
+			PackageJSON:       `{"name": "posh"}`,
+			Files:             map[string]time.Time{"package.json": installed, "yarn.lock": installed, "package-lock.json": installed},
+			ExpectedName:      "yarn",
+			ExpectedIcon:      "yarn",
+			ExpectedLockFiles: []string{"yarn.lock", "package-lock.json"},
+			ExpectedMixed:     true,
+		},
+		{
+			Case:              "bun lock files",
+			Files:             map[string]time.Time{"bun.lockb": installed, "bun.lock": installed},
+			ExpectedName:      "bun",
+			ExpectedIcon:      "bun",
+			ExpectedLockFiles: []string{"bun.lockb", "bun.lock"},
+		},
+		{
+			Case:              "node_modules up to date",
+			Files:             map[string]time.Time{"package-lock.json": installed, "node_modules": installed},
+			NodeModules:       map[string]time.Time{".package-lock.json": changed},
+			ExpectedName:      "npm",
+			ExpectedIcon:      "npm",
+			ExpectedLockFiles: []string{"package-lock.json"},
+		},
+		{
+			Case:              "node_modules outdated",
+			Files:             map[string]time.Time{"pnpm-lock.yaml": changed, "node_modules": changed},
+			NodeModules:       map[string]time.Time{".modules.yaml": installed},
+			ExpectedName:      "pnpm",
+			ExpectedIcon:      "pnpm",
+			ExpectedLockFiles: []string{"pnpm-lock.yaml"},
+			ExpectedOutdated:  true,
+		},
+		{
+			Case:              "node_modules outdated without state file",
+			Files:             map[string]time.Time{"yarn.lock": changed, "node_modules": installed},
+			ExpectedName:      "yarn",
+			ExpectedIcon:      "yarn",
+			ExpectedLockFiles: []string{"yarn.lock"},
+			ExpectedOutdated:  true,
+		},
+		{
+			Case:              "no node_modules",
+			Files:             map[string]time.Time{"yarn.lock": changed},
+			ExpectedName:      "yarn",
+			ExpectedIcon:      "yarn",
+			ExpectedLockFiles: []string{"yarn.lock"},
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Pwd").Return("/posh")
+
+		for _, pm := range nodePackageManagers {
+			for _, lockFile := range pm.lockFiles {
+				_, ok := tc.Files[lockFile]
+				env.On("HasFiles", lockFile).Return(ok)
+			}
+		}
+
+		_, hasPackageJSON := tc.Files["package.json"]
+		env.On("HasFiles", "package.json").Return(hasPackageJSON)
+		env.On("FileContent", "package.json").Return(tc.PackageJSON)
+
+		env.On("LsDir", "/posh").Return(nodeDirEntries(tc.Files, "node_modules"))
+		env.On("LsDir", "/posh/node_modules").Return(nodeDirEntries(tc.NodeModules, ""))
+
+		props := properties.Map{
+			PnpmIcon:            "pnpm",
+			YarnIcon:            "yarn",
+			NPMIcon:             "npm",
+			BunIcon:             "bun",
+			DenoIcon:            "deno",
+			FetchPackageManager: true,
+		}
+
+		node := &Node{}
+		node.Init(props, env)
+
+		node.loadContext()
+		assert.Equal(t, tc.ExpectedName, node.PackageManagerName, tc.Case)
+		assert.Equal(t, tc.ExpectedIcon, node.PackageManagerIcon, tc.Case)
+		assert.Equal(t, tc.ExpectedVersion, node.PackageManagerVersion, tc.Case)
+		assert.Equal(t, tc.ExpectedLockFiles, node.LockFiles, tc.Case)
+		assert.Equal(t, tc.ExpectedCorepack, node.Corepack, tc.Case)
+		assert.Equal(t, tc.ExpectedMixed, node.MixedLockFiles, tc.Case)
+		assert.Equal(t, tc.ExpectedOutdated, node.NodeModulesOutdated, tc.Case)
+	}
+}
+
+func nodeDirEntries(files map[string]time.Time, dir string) []fs.DirEntry {
+	mapFS := fstest.MapFS{}
+	for name, modTime := range files {
+		mapFS[name] = &fstest.MapFile{ModTime: modTime}
+	}
+
+	var entries []fs.DirEntry
+	for name := range files {
+		info, _ := fs.Stat(mapFS, name)
+		entries = append(entries, &MockDirEntry{name: name, isDir: name == dir, fileInfo: info})
+	}
+
+	return entries
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jandedobbeleer/oh-my-posh/src/regex"
 
 	toml "github.com/pelletier/go-toml/v2"
+	"golang.org/x/mod/modfile"
 	yaml "gopkg.in/yaml.v3"
 )
 
@@ -44,6 +45,17 @@ type PyProjectToolTOML struct {
 	Poetry ProjectData
 }
 
+// Maven project
+type MavenPOM struct {
+	XMLName xml.Name `xml:"project"`
+	Parent  struct {
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	ArtifactID string `xml:"artifactId"`
+	Name       string `xml:"name"`
+	Version    string `xml:"version"`
+}
+
 type NuSpec struct {
 	XMLName  xml.Name `xml:"package"`
 	MetaData struct {
@@ -55,11 +67,19 @@ type NuSpec struct {
 type Project struct {
 	Base
 
+	Workspace *ProjectWorkspace
 	ProjectData
 	Error    string
+	Path     string
+	dir      string
 	projects []*ProjectItem
 }
 
+const (
+	// FetchWorkspace looks for the workspace or monorepo holding the current package
+	FetchWorkspace properties.Property = "fetch_workspace"
+)
+
 func (n *Project) Enabled() bool {
 	n.projects = []*ProjectItem{
 		{
@@ -112,12 +132,50 @@ func (n *Project) Enabled() bool {
 			Files:   []string{"*.psd1"},
 			Fetcher: n.getPowerShellModuleData,
 		},
+		{
+			Name:    "go",
+			Files:   []string{"go.mod"},
+			Fetcher: n.getGoModule,
+		},
+		{
+			Name:    "maven",
+			Files:   []string{"pom.xml"},
+			Fetcher: n.getMavenProject,
+		},
+		{
+			Name:    "gradle",
+			Files:   []string{"build.gradle", "build.gradle.kts"},
+			Fetcher: n.getGradleProject,
+		},
+		{
+			Name:    "mix",
+			Files:   []string{"mix.exs"},
+			Fetcher: n.getMixProject,
+		},
 	}
 
 	for _, item := range n.projects {
 		// allow files override
 		property := properties.Property(fmt.Sprintf("%s_files", item.Name))
 		item.Files = n.props.GetStringArray(property, item.Files)
+	}
+
+	enabled := n.setProjectData()
+
+	if n.props.GetBool(FetchWorkspace, false) && n.setWorkspace() {
+		return true
+	}
+
+	return enabled || n.props.GetBool(properties.AlwaysEnabled, false)
+}
+
+// setProjectData reads the first known project file in n.dir, the current folder when not set.
+func (n *Project) setProjectData() bool {
+	for _, item := range n.projects {
+		// the glob based projects can only be read from the current folder
+		if len(n.dir) != 0 && slices.ContainsFunc(item.Files, func(file string) bool { return strings.Contains(file, "*") }) {
+			continue
+		}
 
 		if !n.hasProjectFile(item) {
 			continue
@@ -133,7 +191,7 @@ func (n *Project) Enabled() bool {
 		return true
 	}
 
-	return n.props.GetBool(properties.AlwaysEnabled, false)
+	return false
 }
 
 func (n *Project) Template() string {
@@ -141,11 +199,24 @@ func (n *Project) Template() string {
 }
 
 func (n *Project) hasProjectFile(p *ProjectItem) bool {
-	return slices.ContainsFunc(p.Files, n.env.HasFiles)
+	return slices.ContainsFunc(p.Files, n.hasFile)
+}
+
+func (n *Project) hasFile(file string) bool {
+	if len(n.dir) == 0 {
+		return n.env.HasFiles(file)
+	}
+
+	return n.env.HasFilesInDir(n.dir, file)
+}
+
+// fileContent reads file from n.dir, the current folder when not set.
+func (n *Project) fileContent(file string) string {
+	return n.env.FileContent(filepath.Join(n.dir, file))
 }
 
 func (n *Project) getNodePackage(item ProjectItem) *ProjectData {
-	content := n.env.FileContent(item.Files[0])
+	content := n.fileContent(item.Files[0])
 
 	var data ProjectData
 	err := json.Unmarshal([]byte(content), &data)
@@ -158,7 +229,7 @@ func (n *Project) getNodePackage(item ProjectItem) *ProjectData {
 }
 
 func (n *Project) getCargoPackage(item ProjectItem) *ProjectData {
-	content := n.env.FileContent(item.Files[0])
+	content := n.fileContent(item.Files[0])
 
 	var data CargoTOML
 	err := toml.Unmarshal([]byte(content), &data)
@@ -174,7 +245,7 @@ func (n *Project) getCargoPackage(item ProjectItem) *ProjectData {
 }
 
 func (n *Project) getPythonPackage(item ProjectItem) *ProjectData {
-	content := n.env.FileContent(item.Files[0])
+	content := n.fileContent(item.Files[0])
 
 	var data PyProjectTOML
 	err := toml.Unmarshal([]byte(content), &data)
@@ -196,7 +267,7 @@ func (n *Project) getPythonPackage(item ProjectItem) *ProjectData {
 }
 
 func (n *Project) getDartPackage(item ProjectItem) *ProjectData {
-	content := n.env.FileContent(item.Files[0])
+	content := n.fileContent(item.Files[0])
 	var data ProjectData
 	err := yaml.Unmarshal([]byte(content), &data)
 	if err != nil {
@@ -314,7 +385,7 @@ func (n *Project) getPowerShellModuleData(_ ProjectItem) *ProjectData {
 }
 
 func (n *Project) getProjectData(item ProjectItem) *ProjectData {
-	content := n.env.FileContent(item.Files[0])
+	content := n.fileContent(item.Files[0])
 
 	var data ProjectData
 	err := toml.Unmarshal([]byte(content), &data)
@@ -325,3 +396,86 @@ func (n *Project) getProjectData(item ProjectItem) *ProjectData {
 
 	return &data
 }
+
+func (n *Project) getGoModule(item ProjectItem) *ProjectData {
+	content := n.fileContent(item.Files[0])
+
+	file, err := modfile.ParseLax(item.Files[0], []byte(content), nil)
+	if err != nil {
+		n.Error = err.Error()
+		return nil
+	}
+
+	data := &ProjectData{}
+
+	if file.Module != nil {
+		data.Name = file.Module.Mod.Path
+	}
+
+	if file.Go != nil {
+		data.Target = file.Go.Version
+	}
+
+	return data
+}
+
+func (n *Project) getMavenProject(item ProjectItem) *ProjectData {
+	content := n.fileContent(item.Files[0])
+
+	var data MavenPOM
+	err := xml.Unmarshal([]byte(content), &data)
+	if err != nil {
+		n.Error = err.Error()
+		return nil
+	}
+
+	project := &ProjectData{
+		Name:    data.Name,
+		Version: data.Version,
+	}
+
+	if len(project.Name) == 0 {
+		project.Name = data.ArtifactID
+	}
+
+	// modules inherit the version of their parent
+	if len(project.Version) == 0 {
+		project.Version = data.Parent.Version
+	}
+
+	return project
+}
+
+func (n *Project) getGradleProject(item ProjectItem) *ProjectData {
+	var content string
+	for _, file := range item.Files {
+		if n.hasFile(file) {
+			content = n.fileContent(file)
+			break
+		}
+	}
+
+	data := &ProjectData{}
+	data.Version = regex.FindNamedRegexMatch(`(?m)^\s*version\s*=?\s*["'](?P<version>[^"']+)["']`, content)["version"]
+
+	// the name is set in the settings of the build
+	for _, file := range []string{"settings.gradle", "settings.gradle.kts"} {
+		if !n.hasFile(file) {
+			continue
+		}
+
+		data.Name = regex.FindNamedRegexMatch(`rootProject\.name\s*=\s*["'](?P<name>[^"']+)["']`, n.fileContent(file))["name"]
+		break
+	}
+
+	return data
+}
+
+func (n *Project) getMixProject(item ProjectItem) *ProjectData {
+	content := n.fileContent(item.Files[0])
+
+	return &ProjectData{
+		Name:    regex.FindNamedRegexMatch(`app:\s*:(?P<name>\w+)`, content)["name"],
+		Version: regex.FindNamedRegexMatch(`version:\s*"(?P<version>[^"]+)"`, content)["version"],
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/alecthomas/assert"
 
 	testify_ "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -330,6 +331,54 @@ func TestPackage(t *testing.T) {
 			File:            "JuliaProject.toml",
 			PackageContents: "name = \"ProjectEuler\"",
 		},
+		{
+			Case:            "Go module",
+			ExpectedEnabled: true,
+			ExpectedString:  "github.com/posh/forge \uf4de 1.22",
+			Name:            "go",
+			File:            "go.mod",
+			PackageContents: "module github.com/posh/forge\n\ngo 1.22\n",
+		},
+		{
+			Case:            "Maven project inheriting the version",
+			ExpectedEnabled: true,
+			ExpectedString:  "\uf487 2.0.0 core",
+			Name:            "maven",
+			File:            "pom.xml",
+			PackageContents: `<project xmlns="http://maven.apache.org/POM/4.0.0"><parent><version>2.0.0</version></parent><artifactId>core</artifactId></project>`,
+		},
+		{
+			Case:            "Maven project with a name",
+			ExpectedEnabled: true,
+			ExpectedString:  "\uf487 1.0.0 Posh Core",
+			Name:            "maven",
+			File:            "pom.xml",
+			PackageContents: `<project><artifactId>core</artifactId><name>Posh Core</name><version>1.0.0</version></project>`,
+		},
+		{
+			Case:            "Gradle project",
+			ExpectedEnabled: true,
+			ExpectedString:  "\uf487 1.2.0",
+			Name:            "gradle",
+			File:            "build.gradle",
+			PackageContents: "plugins {\n  id 'java'\n}\n\nversion '1.2.0'\n",
+		},
+		{
+			Case:            "Gradle Kotlin project",
+			ExpectedEnabled: true,
+			ExpectedString:  "\uf487 1.3.0",
+			Name:            "gradle",
+			File:            "build.gradle.kts",
+			PackageContents: "group = \"dev.posh\"\nversion = \"1.3.0\"\n",
+		},
+		{
+			Case:            "Mix project",
+			ExpectedEnabled: true,
+			ExpectedString:  "\uf487 0.4.1 forge",
+			Name:            "mix",
+			File:            "mix.exs",
+			PackageContents: "def project do\n  [\n    app: :forge,\n    version: \"0.4.1\",\n    elixir: \"~> 1.16\"\n  ]\nend\n",
+		},
 		{
 			Case:            "Julia project invalid toml",
 			ExpectedString:  "toml: line 1: unexpected end of table name (table names cannot be empty)",
@@ -540,3 +589,203 @@ func TestPowerShellModuleProject(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectWorkspace(t *testing.T) {
+	cases := []struct {
+		Files             map[string]string
+		Case              string
+		Pwd               string
+		ExpectedType      string
+		ExpectedName      string
+		ExpectedVersion   string
+		ExpectedRoot      string
+		ExpectedPath      string
+		ExpectedMember    string
+		ExpectedWorkspace bool
+	}{
+		{
+			Case:  "No workspace",
+			Pwd:   "/repo",
+			Files: map[string]string{"/repo/package.json": `{"name":"app","version":"1.0.0"}`},
+		},
+		{
+			Case: "npm workspace member",
+			Pwd:  "/repo/packages/ui",
+			Files: map[string]string{
+				"/repo/package.json":             `{"name":"monorepo","version":"2.0.0","workspaces":["packages/*"]}`,
+				"/repo/packages/ui/package.json": `{"name":"@posh/ui","version":"0.3.0"}`,
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "npm",
+			ExpectedName:      "monorepo",
+			ExpectedVersion:   "2.0.0",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "packages/ui",
+			ExpectedMember:    "@posh/ui",
+		},
+		{
+			Case: "Yarn workspace from a member subfolder",
+			Pwd:  "/repo/packages/ui/src/components",
+			Files: map[string]string{
+				"/repo/package.json":             `{"name":"monorepo","workspaces":{"packages":["packages/*"]}}`,
+				"/repo/yarn.lock":                "",
+				"/repo/packages/ui/package.json": `{"name":"@posh/ui","version":"0.3.0"}`,
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "yarn",
+			ExpectedName:      "monorepo",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "packages/ui",
+			ExpectedMember:    "@posh/ui",
+		},
+		{
+			Case: "Nx wins over pnpm",
+			Pwd:  "/repo",
+			Files: map[string]string{
+				"/repo/nx.json":             "{}",
+				"/repo/pnpm-workspace.yaml": "packages:\n  - apps/*\n",
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "nx",
+			ExpectedName:      "repo",
+			ExpectedRoot:      "/repo",
+		},
+		{
+			Case: "Turborepo",
+			Pwd:  "/repo/apps/web",
+			Files: map[string]string{
+				"/repo/turbo.json":            "{}",
+				"/repo/package.json":          `{"name":"acme"}`,
+				"/repo/apps/web/package.json": `{"name":"web","version":"1.1.0"}`,
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "turbo",
+			ExpectedName:      "acme",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "apps/web",
+			ExpectedMember:    "web",
+		},
+		{
+			Case: "Turborepo package configuration",
+			Pwd:  "/repo/apps/web",
+			Files: map[string]string{
+				"/repo/turbo.json":            "{}",
+				"/repo/package.json":          `{"name":"acme","workspaces":["apps/*"]}`,
+				"/repo/apps/web/turbo.json":   `{"extends":["//"],"tasks":{"build":{"outputs":[".next/**"]}}}`,
+				"/repo/apps/web/package.json": `{"name":"web","version":"1.1.0"}`,
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "turbo",
+			ExpectedName:      "acme",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "apps/web",
+			ExpectedMember:    "web",
+		},
+		{
+			Case: "Cargo workspace",
+			Pwd:  "/repo/crates/cli",
+			Files: map[string]string{
+				"/repo/Cargo.toml":            "[workspace]\nmembers = [\"crates/*\"]\n\n[workspace.package]\nversion = \"0.9.0\"\n",
+				"/repo/crates/cli/Cargo.toml": "[package]\nname = \"cli\"\nversion = \"0.9.0\"\n",
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "cargo",
+			ExpectedName:      "repo",
+			ExpectedVersion:   "0.9.0",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "crates/cli",
+			ExpectedMember:    "cli",
+		},
+		{
+			Case: "Cargo package without workspace",
+			Pwd:  "/repo",
+			Files: map[string]string{
+				"/repo/Cargo.toml": "[package]\nname = \"cli\"\n",
+			},
+		},
+		{
+			Case: "Go workspace",
+			Pwd:  "/repo/tools",
+			Files: map[string]string{
+				"/repo/go.work":        "go 1.22\n\nuse ./tools\n",
+				"/repo/tools/go.mod":   "module example.com/tools\n\ngo 1.22\n",
+				"/repo/tools/main.go":  "",
+				"/repo/other/go.mod":   "module example.com/other\n",
+				"/repo/other/other.go": "",
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "go",
+			ExpectedName:      "repo",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "tools",
+			ExpectedMember:    "example.com/tools",
+		},
+		{
+			Case: "uv workspace",
+			Pwd:  "/repo/libs/core",
+			Files: map[string]string{
+				"/repo/pyproject.toml":           "[project]\nname = \"platform\"\nversion = \"1.0.0\"\n\n[tool.uv.workspace]\nmembers = [\"libs/*\"]\n",
+				"/repo/libs/core/pyproject.toml": "[project]\nname = \"core\"\nversion = \"0.2.0\"\n",
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "uv",
+			ExpectedName:      "platform",
+			ExpectedVersion:   "1.0.0",
+			ExpectedRoot:      "/repo",
+			ExpectedPath:      "libs/core",
+			ExpectedMember:    "core",
+		},
+		{
+			Case: "Poetry monorepo",
+			Pwd:  "/repo",
+			Files: map[string]string{
+				"/repo/pyproject.toml": "[tool.poetry]\nname = \"platform\"\nversion = \"1.0.0\"\n\n[tool.poetry.dependencies]\npython = \"^3.12\"\ncore = { path = \"libs/core\", develop = true }\n",
+			},
+			ExpectedWorkspace: true,
+			ExpectedType:      "poetry",
+			ExpectedName:      "platform",
+			ExpectedVersion:   "1.0.0",
+			ExpectedRoot:      "/repo",
+			ExpectedMember:    "platform",
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Pwd").Return(tc.Pwd)
+
+		for file, content := range tc.Files {
+			dir, name := filepath.Dir(file), filepath.Base(file)
+			env.On("HasFilesInDir", dir, name).Return(true)
+			env.On("FileContent", file).Return(content)
+
+			if dir == tc.Pwd {
+				env.On(hasFiles, name).Return(true)
+				env.On("FileContent", name).Return(content)
+			}
+		}
+
+		env.On(hasFiles, testify_.Anything).Return(false)
+		env.On("HasFilesInDir", testify_.Anything, testify_.Anything).Return(false)
+
+		pkg := &Project{}
+		pkg.Init(properties.Map{FetchWorkspace: true}, env)
+
+		enabled := pkg.Enabled()
+
+		if !tc.ExpectedWorkspace {
+			assert.Nil(t, pkg.Workspace, tc.Case)
+			continue
+		}
+
+		assert.True(t, enabled, tc.Case)
+		require.NotNil(t, pkg.Workspace, tc.Case)
+
+		assert.Equal(t, tc.ExpectedType, pkg.Workspace.Type, tc.Case)
+		assert.Equal(t, tc.ExpectedName, pkg.Workspace.Name, tc.Case)
+		assert.Equal(t, tc.ExpectedVersion, pkg.Workspace.Version, tc.Case)
+		assert.Equal(t, tc.ExpectedRoot, pkg.Workspace.Root, tc.Case)
+		assert.Equal(t, tc.ExpectedPath, pkg.Path, tc.Case)
+		assert.Equal(t, tc.ExpectedMember, pkg.Name, tc.Case)
+	}
+}
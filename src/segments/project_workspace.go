@@ -0,0 +1,216 @@
+package segments
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// ProjectWorkspace is the root of the workspace or monorepo holding the current package.
+type ProjectWorkspace struct {
+	// Type is the tool managing the workspace: npm, yarn, pnpm, nx, turbo, cargo, go, uv or poetry
+	Type    string
+	Name    string
+	Version string
+	// Root is the folder of the workspace
+	Root string
+}
+
+// setWorkspace looks for the nearest workspace root in the current and parent folders.
+// When the current folder isn't a package, the nearest package between it and the root is the member.
+func (n *Project) setWorkspace() bool {
+	pwd := n.env.Pwd()
+
+	dir := pwd
+	for {
+		if n.Workspace = n.workspaceIn(dir); n.Workspace != nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+
+		dir = parent
+	}
+
+	n.Workspace.Root = dir
+
+	member := pwd
+	if len(n.Type) == 0 {
+		member = n.findMember(pwd, dir)
+	}
+
+	if len(member) == 0 || member == dir {
+		return true
+	}
+
+	if path, err := filepath.Rel(dir, member); err == nil {
+		n.Path = filepath.ToSlash(path)
+	}
+
+	return true
+}
+
+// findMember returns the folder of the nearest package above pwd and below root.
+func (n *Project) findMember(pwd, root string) string {
+	defer func() {
+		n.dir = ""
+	}()
+
+	for dir := filepath.Dir(pwd); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		n.dir = dir
+		if n.setProjectData() {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+func (n *Project) workspaceIn(dir string) *ProjectWorkspace {
+	hasFile := func(file string) bool {
+		return n.env.HasFilesInDir(dir, file)
+	}
+
+	content := func(file string) []byte {
+		return []byte(n.env.FileContent(filepath.Join(dir, file)))
+	}
+
+	workspace := &ProjectWorkspace{Name: filepath.Base(dir)}
+
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+		Name       string          `json:"name"`
+		Version    string          `json:"version"`
+	}
+
+	if hasFile("package.json") && json.Unmarshal(content("package.json"), &pkg) == nil {
+		if len(pkg.Name) != 0 {
+			workspace.Name = pkg.Name
+		}
+
+		workspace.Version = pkg.Version
+	}
+
+	hasWorkspaces := len(pkg.Workspaces) != 0 && string(pkg.Workspaces) != "null"
+	packageManagerRoot := hasWorkspaces || hasFile("pnpm-workspace.yaml")
+
+	// the task runners sit on top of the package manager workspaces
+	switch {
+	case hasFile("nx.json"):
+		workspace.Type = "nx"
+	case hasFile("turbo.json") && (packageManagerRoot || isTurboRoot(content("turbo.json"))):
+		workspace.Type = "turbo"
+	case hasFile("pnpm-workspace.yaml"):
+		workspace.Type = "pnpm"
+	case hasWorkspaces:
+		workspace.Type = "npm"
+		if hasFile("yarn.lock") {
+			workspace.Type = "yarn"
+		}
+	}
+
+	if len(workspace.Type) != 0 {
+		return workspace
+	}
+
+	if hasFile("Cargo.toml") {
+		var cargo struct {
+			Workspace *struct {
+				Package ProjectData `toml:"package"`
+			} `toml:"workspace"`
+			Package ProjectData `toml:"package"`
+		}
+
+		if toml.Unmarshal(content("Cargo.toml"), &cargo) == nil && cargo.Workspace != nil {
+			workspace.Type = "cargo"
+			workspace.Version = cargo.Workspace.Package.Version
+
+			if len(cargo.Package.Name) != 0 {
+				workspace.Name = cargo.Package.Name
+			}
+
+			return workspace
+		}
+	}
+
+	if hasFile("go.work") {
+		workspace.Type = "go"
+		return workspace
+	}
+
+	if hasFile("pyproject.toml") {
+		return pythonWorkspace(workspace, content("pyproject.toml"))
+	}
+
+	return nil
+}
+
+// isTurboRoot tells whether turbo.json configures the workspace, a package in it
+// can have its own turbo.json which extends the one of the root using "extends": ["//"].
+func isTurboRoot(content []byte) bool {
+	var turbo struct {
+		Extends []string `json:"extends"`
+	}
+
+	if err := json.Unmarshal(content, &turbo); err != nil {
+		return true
+	}
+
+	return len(turbo.Extends) == 0
+}
+
+// pythonWorkspace detects uv workspaces and poetry monorepos, which depend on their members by path.
+func pythonWorkspace(workspace *ProjectWorkspace, content []byte) *ProjectWorkspace {
+	var pyproject struct {
+		Tool struct {
+			Uv struct {
+				Workspace *struct {
+					Members []string `toml:"members"`
+				} `toml:"workspace"`
+			} `toml:"uv"`
+			Poetry struct {
+				Dependencies map[string]any `toml:"dependencies"`
+				ProjectData
+			} `toml:"poetry"`
+		} `toml:"tool"`
+		Project ProjectData `toml:"project"`
+	}
+
+	if toml.Unmarshal(content, &pyproject) != nil {
+		return nil
+	}
+
+	project := pyproject.Project
+
+	switch {
+	case pyproject.Tool.Uv.Workspace != nil:
+		workspace.Type = "uv"
+	case hasPathDependency(pyproject.Tool.Poetry.Dependencies):
+		workspace.Type = "poetry"
+		project = pyproject.Tool.Poetry.ProjectData
+	default:
+		return nil
+	}
+
+	if len(project.Name) != 0 {
+		workspace.Name = project.Name
+	}
+
+	workspace.Version = project.Version
+
+	return workspace
+}
+
+func hasPathDependency(dependencies map[string]any) bool {
+	for _, dependency := range dependencies {
+		if table, ok := dependency.(map[string]any); ok && table["path"] != nil {
+			return true
+		}
+	}
+
+	return false
+}
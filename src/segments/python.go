@@ -13,10 +13,21 @@ import (
 )
 
 type Python struct {
-	Venv string
+	pyvenv *pyvenvCfg
+	Venv   string
+	// Manager is the tool managing the environment: uv, poetry, pipenv, conda, mamba, pixi or hatch
+	Manager string
+	// Implementation is the interpreter, like CPython or PyPy
+	Implementation string
 	Language
 }
 
+// pyvenvCfg holds the settings of the virtual env the python executable belongs to.
+type pyvenvCfg struct {
+	values map[string]string
+	dir    string
+}
+
 const (
 	// FetchVirtualEnv fetches the virtual env
 	FetchVirtualEnv      properties.Property = "fetch_virtual_env"
@@ -25,6 +36,12 @@ const (
 	DefaultVenvNames     properties.Property = "default_venv_names"
 )
 
+var venvVars = []string{
+	"VIRTUAL_ENV",
+	"CONDA_ENV_PATH",
+	"CONDA_DEFAULT_ENV",
+}
+
 func (p *Python) Template() string {
 	return " {{ if .Error }}{{ .Error }}{{ else }}{{ if .Venv }}{{ .Venv }} {{ end }}{{ .Full }}{{ end }} "
 }
@@ -55,25 +72,40 @@ func (p *Python) Enabled() bool {
 	}
 	p.versionURLTemplate = "https://docs.python.org/release/{{ .Major }}.{{ .Minor }}.{{ .Patch }}/whatsnew/changelog.html#python-{{ .Major }}-{{ .Minor }}-{{ .Patch }}"
 	p.displayMode = p.props.GetString(DisplayMode, DisplayModeEnvironment)
+	p.toolchain = p.pythonToolchain()
+	p.constraints = []*versionConstraint{
+		{file: "pyproject.toml", source: "requires-python", parse: requiresPythonConstraint},
+		{file: "pyproject.toml", source: "tool.poetry.dependencies.python", parse: poetryPythonConstraint},
+		{file: "uv.lock", source: "requires-python", parse: uvLockConstraint},
+		{file: "poetry.lock", source: "metadata.python-versions", parse: poetryLockConstraint},
+		{file: "Pipfile.lock", source: "_meta.requires.python_version", parse: pipfileLockConstraint},
+	}
 	p.Language.loadContext = p.loadContext
 	p.Language.inContext = p.inContext
 
-	return p.Language.Enabled()
+	if !p.Language.Enabled() {
+		return false
+	}
+
+	p.setManager()
+	p.setImplementation()
+
+	return true
 }
 
 func (p *Python) loadContext() {
 	if !p.props.GetBool(FetchVirtualEnv, true) {
 		return
 	}
-	if prompt := p.pyvenvCfgPrompt(); len(prompt) > 0 {
-		p.Venv = prompt
-		return
-	}
 
-	venvVars := []string{
-		"VIRTUAL_ENV",
-		"CONDA_ENV_PATH",
-		"CONDA_DEFAULT_ENV",
+	if p.pyvenv = p.readPyvenvCfg(); p.pyvenv != nil {
+		// the python executable belongs to the virtual env, not to the version manager
+		p.toolchain = &toolchain{resolve: p.pyvenvToolchain}
+
+		if prompt := p.pyvenv.values["prompt"]; len(prompt) > 0 {
+			p.Venv = prompt
+			return
+		}
 	}
 
 	folderNameFallback := p.props.GetBool(FolderNameFallback, true)
@@ -104,6 +136,23 @@ func (p *Python) loadContext() {
 	}
 }
 
+// pythonToolchain resolves pyenv, asdf and mise, unless a virtual env is active.
+func (p *Python) pythonToolchain() *toolchain {
+	for _, venvVar := range venvVars {
+		if len(p.env.Getenv(venvVar)) != 0 {
+			return nil
+		}
+	}
+
+	return &toolchain{
+		tools:    []string{"python"},
+		env:      []string{"PYENV_VERSION"},
+		files:    []string{".python-version"},
+		globals:  []string{"$PYENV_ROOT/version", "~/.pyenv/version"},
+		installs: []string{"$PYENV_ROOT/versions", "~/.pyenv/versions"},
+	}
+}
+
 func (p *Python) inContext() bool {
 	return p.Venv != ""
 }
@@ -174,14 +223,18 @@ func (p *Python) pyenvVersion() (string, error) {
 	return parts[0], nil
 }
 
-func (p *Python) pyvenvCfgPrompt() string {
-	cmdPath := p.env.CommandPath("python")
-	if cmdPath == "" {
-		cmdPath = p.env.CommandPath("python3")
+func (p *Python) executablePath() string {
+	if cmdPath := p.env.CommandPath("python"); len(cmdPath) != 0 {
+		return cmdPath
 	}
 
+	return p.env.CommandPath("python3")
+}
+
+func (p *Python) readPyvenvCfg() *pyvenvCfg {
+	cmdPath := p.executablePath()
 	if cmdPath == "" {
-		return ""
+		return nil
 	}
 
 	pyvenvDir := filepath.Dir(cmdPath)
@@ -190,22 +243,151 @@ func (p *Python) pyvenvCfgPrompt() string {
 	}
 
 	if !p.env.HasFilesInDir(pyvenvDir, "pyvenv.cfg") {
-		return ""
+		return nil
+	}
+
+	cfg := &pyvenvCfg{
+		dir:    pyvenvDir,
+		values: make(map[string]string),
 	}
 
-	pyvenvCfg := p.env.FileContent(filepath.Join(pyvenvDir, "pyvenv.cfg"))
-	for line := range strings.SplitSeq(pyvenvCfg, "\n") {
-		lineSplit := strings.SplitN(line, "=", 2)
-		if len(lineSplit) != 2 {
+	content := p.env.FileContent(filepath.Join(pyvenvDir, "pyvenv.cfg"))
+	for line := range strings.SplitSeq(content, "\n") {
+		key, value, found := strings.Cut(line, "=")
+		if !found {
 			continue
 		}
 
-		key := strings.TrimSpace(lineSplit[0])
-		if key == "prompt" {
-			value := strings.TrimSpace(lineSplit[1])
-			return value
+		cfg.values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return cfg
+}
+
+// pyvenvToolchain uses the version in pyvenv.cfg, written by venv as version and by virtualenv and uv as version_info,
+// so python doesn't need to run. Without it, the version is cached per virtual env.
+func (p *Python) pyvenvToolchain() (*resolvedToolchain, bool) {
+	version := p.pyvenv.values["version_info"]
+	if len(version) == 0 {
+		version = p.pyvenv.values["version"]
+	}
+
+	return &resolvedToolchain{path: p.pyvenv.dir, version: version}, true
+}
+
+// setManager detects the tool managing the active environment from the variables it sets,
+// falling back to the lock or configuration file of the project.
+func (p *Python) setManager() {
+	virtualEnv := len(p.env.Getenv("VIRTUAL_ENV")) != 0
+
+	// a virtual env is activated on top of the conda env, which can be conda's auto activated base env
+	if virtualEnv && p.pyvenv != nil && len(p.pyvenv.values["uv"]) != 0 {
+		p.Manager = "uv"
+		return
+	}
+
+	managers := []struct {
+		env     string
+		manager string
+	}{
+		// pixi also sets the conda variables
+		{env: "PIXI_ENVIRONMENT_NAME", manager: "pixi"},
+		{env: "PIPENV_ACTIVE", manager: "pipenv"},
+		{env: "POETRY_ACTIVE", manager: "poetry"},
+		{env: "HATCH_ENV_ACTIVE", manager: "hatch"},
+	}
+
+	for _, m := range managers {
+		if len(p.env.Getenv(m.env)) != 0 {
+			p.Manager = m.manager
+			return
+		}
+	}
+
+	if manager, OK := p.condaManager(virtualEnv); OK {
+		p.Manager = manager
+		return
+	}
+
+	if p.pyvenv != nil && len(p.pyvenv.values["uv"]) != 0 {
+		p.Manager = "uv"
+		return
+	}
+
+	files := []struct {
+		file    string
+		manager string
+	}{
+		{file: "uv.lock", manager: "uv"},
+		{file: "poetry.lock", manager: "poetry"},
+		{file: "Pipfile.lock", manager: "pipenv"},
+		{file: "Pipfile", manager: "pipenv"},
+		{file: "pixi.lock", manager: "pixi"},
+		{file: "pixi.toml", manager: "pixi"},
+		{file: "hatch.toml", manager: "hatch"},
+		{file: "environment.yml", manager: "conda"},
+	}
+
+	for _, f := range files {
+		if _, err := p.env.HasParentFilePath(f.file, false); err == nil {
+			p.Manager = f.manager
+			return
+		}
+	}
+}
+
+// condaManager tells whether a conda env is active, and whether micromamba activated it.
+// MAMBA_ROOT_PREFIX is exported by micromamba's shell init, so it only counts for an env inside it.
+func (p *Python) condaManager(virtualEnv bool) (string, bool) {
+	prefix := p.env.Getenv("CONDA_PREFIX")
+	name := p.env.Getenv("CONDA_DEFAULT_ENV")
+
+	if len(prefix) == 0 && len(name) == 0 {
+		return "", false
+	}
+
+	// the auto activated base env doesn't manage a virtual env activated on top of it
+	if virtualEnv && (len(name) == 0 || name == "base") {
+		return "", false
+	}
+
+	if root := p.env.Getenv("MAMBA_ROOT_PREFIX"); len(root) != 0 && len(prefix) != 0 && strings.HasPrefix(prefix, root) {
+		return "mamba", true
+	}
+
+	return "conda", true
+}
+
+// setImplementation reads the implementation virtualenv and uv write to pyvenv.cfg,
+// or recognizes it in the path of the interpreter.
+func (p *Python) setImplementation() {
+	paths := []string{p.executablePath()}
+
+	if p.pyvenv != nil {
+		if implementation := p.pyvenv.values["implementation"]; len(implementation) != 0 {
+			p.Implementation = implementation
+			return
 		}
+
+		paths = append(paths, p.pyvenv.values["home"])
+	}
+
+	implementations := map[string]string{
+		"pypy":    "PyPy",
+		"graalpy": "GraalPy",
 	}
 
-	return ""
+	for _, executable := range paths {
+		executable = strings.ToLower(executable)
+		for marker, implementation := range implementations {
+			if strings.Contains(executable, marker) {
+				p.Implementation = implementation
+				return
+			}
+		}
+	}
+
+	if len(p.Full) != 0 {
+		p.Implementation = "CPython"
+	}
 }
@@ -114,6 +114,8 @@ func TestPythonTemplate(t *testing.T) {
 		props[UsePythonVersionFile] = true
 		props[DisplayMode] = DisplayModeAlways
 
+		mockNoToolchain(env)
+
 		python := &Python{}
 		python.Init(props, env)
 		assert.Equal(t, !tc.ExpectedDisabled, python.Enabled(), tc.Case)
@@ -141,6 +143,8 @@ func TestPythonPythonInContext(t *testing.T) {
 		env.On("Getenv", "CONDA_DEFAULT_ENV").Return("")
 		env.On("Getenv", "PYENV_VERSION").Return("")
 		env.On("HasParentFilePath", ".python-version", false).Return(&runtime.FileInfo{}, errors.New("no match at root level"))
+		mockNoToolchain(env)
+
 		python := &Python{}
 		python.Init(properties.Map{}, env)
 		python.loadContext()
@@ -192,6 +196,8 @@ func TestPythonVirtualEnvIgnoreDefaultVenvNames(t *testing.T) {
 
 		props[FolderNameFallback] = tc.FolderNameFallback
 
+		mockNoToolchain(env)
+
 		python := &Python{}
 		python.Init(props, env)
 		python.loadContext()
@@ -237,9 +243,174 @@ func TestPythonVirtualEnvIgnoreCustomVenvNames(t *testing.T) {
 		props[FolderNameFallback] = tc.FolderNameFallback
 		props[DefaultVenvNames] = tc.DefaultVenvNames
 
+		mockNoToolchain(env)
+
 		python := &Python{}
 		python.Init(props, env)
 		python.loadContext()
 		assert.Equal(t, tc.Expected, python.Venv)
 	}
 }
+
+func TestPythonEnvironment(t *testing.T) {
+	cases := []struct {
+		Env                    map[string]string
+		Files                  map[string]string
+		Case                   string
+		PythonPath             string
+		PyvenvCfg              string
+		ExpectedVersion        string
+		ExpectedVenv           string
+		ExpectedManager        string
+		ExpectedImplementation string
+		ExpectedExpected       string
+		ExpectedMismatch       bool
+	}{
+		{
+			Case:                   "uv virtual env",
+			PyvenvCfg:              "home = /usr/bin\nimplementation = CPython\nuv = 0.4.0\nversion_info = 3.12.4\nprompt = posh\n",
+			ExpectedVersion:        "3.12.4",
+			ExpectedVenv:           "posh",
+			ExpectedManager:        "uv",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "venv outside the uv lock",
+			PyvenvCfg:              "home = /usr/bin\nversion = 3.12.1\n",
+			Files:                  map[string]string{"uv.lock": "version = 1\nrequires-python = \">=3.13\"\n"},
+			ExpectedVersion:        "3.12.1",
+			ExpectedVenv:           "posh",
+			ExpectedManager:        "uv",
+			ExpectedImplementation: "CPython",
+			ExpectedMismatch:       true,
+			ExpectedExpected:       ">=3.13",
+		},
+		{
+			Case:                   "Poetry with PyPy",
+			Env:                    map[string]string{"POETRY_ACTIVE": "1"},
+			PyvenvCfg:              "home = /opt/pypy/bin\nimplementation = PyPy\nversion_info = 3.10.14.final.0\nvirtualenv = 20.26.0\n",
+			Files:                  map[string]string{"poetry.lock": "[metadata]\npython-versions = \"^3.10\"\n"},
+			ExpectedVersion:        "3.10.14",
+			ExpectedVenv:           "posh",
+			ExpectedManager:        "poetry",
+			ExpectedImplementation: "PyPy",
+		},
+		{
+			Case:                   "Pipenv lock",
+			PyvenvCfg:              "home = /usr/bin\nversion = 3.11.9\n",
+			Files:                  map[string]string{"Pipfile.lock": `{"_meta":{"requires":{"python_version":"3.12"}}}`},
+			ExpectedVersion:        "3.11.9",
+			ExpectedVenv:           "posh",
+			ExpectedManager:        "pipenv",
+			ExpectedImplementation: "CPython",
+			ExpectedMismatch:       true,
+			ExpectedExpected:       "~3.12",
+		},
+		{
+			Case:                   "Conda",
+			PythonPath:             "/opt/conda/envs/posh/bin/python",
+			Env:                    map[string]string{"CONDA_PREFIX": "/opt/conda/envs/posh", "CONDA_DEFAULT_ENV": "posh"},
+			ExpectedVersion:        "3.8.4",
+			ExpectedVenv:           "posh",
+			ExpectedManager:        "conda",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "Mamba",
+			PythonPath:             "/opt/micromamba/envs/posh/bin/python",
+			Env:                    map[string]string{"MAMBA_ROOT_PREFIX": "/opt/micromamba", "CONDA_PREFIX": "/opt/micromamba/envs/posh"},
+			ExpectedVersion:        "3.8.4",
+			ExpectedManager:        "mamba",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "uv virtual env on top of the conda base env",
+			PyvenvCfg:              "home = /opt/conda/bin\nimplementation = CPython\nuv = 0.4.0\nversion_info = 3.12.4\nprompt = posh\n",
+			Env:                    map[string]string{"CONDA_PREFIX": "/opt/conda", "CONDA_DEFAULT_ENV": "base"},
+			ExpectedVersion:        "3.12.4",
+			ExpectedVenv:           "posh",
+			ExpectedManager:        "uv",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "venv on top of the micromamba base env",
+			PyvenvCfg:              "home = /usr/bin\nversion = 3.12.1\n",
+			Env:                    map[string]string{"MAMBA_ROOT_PREFIX": "/opt/micromamba", "CONDA_PREFIX": "/opt/micromamba", "CONDA_DEFAULT_ENV": "base"},
+			ExpectedVersion:        "3.12.1",
+			ExpectedVenv:           "posh",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "micromamba shell init without an active env",
+			Env:                    map[string]string{"MAMBA_ROOT_PREFIX": "/opt/micromamba"},
+			ExpectedVersion:        "3.8.4",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "Pixi",
+			PythonPath:             "/posh/.pixi/envs/default/bin/python",
+			Env:                    map[string]string{"PIXI_ENVIRONMENT_NAME": "default", "CONDA_PREFIX": "/posh/.pixi/envs/default"},
+			ExpectedVersion:        "3.8.4",
+			ExpectedManager:        "pixi",
+			ExpectedImplementation: "CPython",
+		},
+		{
+			Case:                   "PyPy executable",
+			PythonPath:             "/opt/pypy3.10/bin/python",
+			ExpectedVersion:        "3.8.4",
+			ExpectedImplementation: "PyPy",
+		},
+	}
+
+	for _, tc := range cases {
+		params := &mockedLanguageParams{
+			cmd:           "python",
+			versionParam:  "--version",
+			versionOutput: "Python 3.8.4",
+			extension:     "*.py",
+		}
+		env, props := getMockedLanguageEnv(params)
+
+		pythonPath := tc.PythonPath
+		if len(pythonPath) == 0 {
+			pythonPath = "/posh/.venv/bin/python"
+		}
+
+		venv := filepath.Dir(filepath.Dir(pythonPath))
+
+		env.On("GOOS").Return("")
+		env.On("CommandPath", "python").Return(pythonPath)
+		env.On("HasFilesInDir", filepath.Dir(pythonPath), "pyvenv.cfg").Return(false)
+		env.On("HasFilesInDir", venv, "pyvenv.cfg").Return(len(tc.PyvenvCfg) != 0)
+		env.On("FileContent", filepath.Join(venv, "pyvenv.cfg")).Return(tc.PyvenvCfg)
+
+		if len(tc.PyvenvCfg) != 0 {
+			env.On("Getenv", "VIRTUAL_ENV").Return(venv)
+		}
+
+		for key, value := range tc.Env {
+			env.On("Getenv", key).Return(value)
+		}
+
+		for file, content := range tc.Files {
+			env.On("HasParentFilePath", file, false).Return(&runtime.FileInfo{Path: "/posh/" + file}, nil)
+			env.On("FileContent", "/posh/"+file).Return(content)
+		}
+
+		mockNoToolchain(env)
+
+		props[DisplayMode] = DisplayModeAlways
+		props[properties.DisplayDefault] = true
+
+		python := &Python{}
+		python.Init(props, env)
+
+		assert.True(t, python.Enabled(), tc.Case)
+		assert.Equal(t, tc.ExpectedVersion, python.Full, tc.Case)
+		assert.Equal(t, tc.ExpectedVenv, python.Venv, tc.Case)
+		assert.Equal(t, tc.ExpectedManager, python.Manager, tc.Case)
+		assert.Equal(t, tc.ExpectedImplementation, python.Implementation, tc.Case)
+		assert.Equal(t, tc.ExpectedMismatch, python.Mismatch, tc.Case)
+		assert.Equal(t, tc.ExpectedExpected, python.Expected, tc.Case)
+	}
+}
@@ -38,6 +38,15 @@ func (r *Ruby) Enabled() bool {
 		},
 	}
 
+	r.toolchain = &toolchain{
+		tools:    []string{"ruby"},
+		active:   []string{"RUBY_ROOT"},
+		env:      []string{"RBENV_VERSION"},
+		files:    []string{".ruby-version"},
+		globals:  []string{"$RBENV_ROOT/version", "~/.rbenv/version"},
+		installs: []string{"$RBENV_ROOT/versions", "~/.rbenv/versions", "~/.rubies"},
+	}
+
 	enabled := r.Language.Enabled()
 
 	// this happens when no version is set
@@ -97,6 +97,8 @@ func TestRuby(t *testing.T) {
 
 		props[properties.FetchVersion] = tc.FetchVersion
 
+		mockNoToolchain(env)
+
 		ruby := &Ruby{}
 		ruby.Init(props, env)
 
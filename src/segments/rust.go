@@ -18,5 +18,16 @@ func (r *Rust) Enabled() bool {
 		},
 	}
 
+	r.constraints = []*versionConstraint{
+		{file: "Cargo.toml", source: "rust-version", parse: rustVersionConstraint},
+	}
+	r.toolchain = &toolchain{
+		tools:    []string{"rust"},
+		env:      []string{"RUSTUP_TOOLCHAIN"},
+		files:    []string{"rust-toolchain.toml", "rust-toolchain"},
+		globals:  []string{"$RUSTUP_HOME/settings.toml", "~/.rustup/settings.toml"},
+		installs: []string{"$RUSTUP_HOME/toolchains", "~/.rustup/toolchains"},
+	}
+
 	return r.Language.Enabled()
 }
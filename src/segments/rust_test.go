@@ -44,6 +44,8 @@ func TestRust(t *testing.T) {
 			extension:     "*.rs",
 		}
 		env, props := getMockedLanguageEnv(params)
+		mockNoToolchain(env)
+
 		r := &Rust{}
 		r.Init(props, env)
 		assert.True(t, r.Enabled(), fmt.Sprintf("Failed in case: %s", tc.Case))
@@ -16,7 +16,19 @@ type Terraform struct {
 	Base
 
 	TerraformBlock
+	// Tool is terraform or opentofu
+	Tool          string
 	WorkspaceName string
+	// Backend is the type of the backend initialized in .terraform, like s3 or remote
+	Backend         string
+	Organization    string
+	RemoteWorkspace string
+	Providers       []*TerraformProvider
+	// PlanFile is the most recent plan in the current folder
+	PlanFile    string
+	Environment string
+	PlanStale   bool
+	Terragrunt  bool
 }
 
 func (tf *Terraform) Template() string {
@@ -32,7 +44,7 @@ type TerraformBlock struct {
 }
 
 func (tf *Terraform) Enabled() bool {
-	cmd := tf.props.GetString(Command, "terraform")
+	cmd := tf.command()
 	fetchVersion := tf.props.GetBool(properties.FetchVersion, false)
 
 	if !tf.env.HasCommand(cmd) || !tf.inContext(fetchVersion) {
@@ -40,6 +52,13 @@ func (tf *Terraform) Enabled() bool {
 	}
 
 	tf.WorkspaceName, _ = tf.env.RunCommand(cmd, "workspace", "show")
+
+	tf.setProviders()
+	tf.setTool(cmd)
+	tf.setBackend()
+	tf.setPlan()
+	tf.setEnvironment()
+
 	if !fetchVersion {
 		return true
 	}
@@ -52,6 +71,19 @@ func (tf *Terraform) Enabled() bool {
 	return true
 }
 
+// command returns the configured command, or tofu when terraform isn't installed.
+func (tf *Terraform) command() string {
+	if cmd := tf.props.GetString(Command, ""); len(cmd) != 0 {
+		return cmd
+	}
+
+	if !tf.env.HasCommand("terraform") && tf.env.HasCommand("tofu") {
+		return "tofu"
+	}
+
+	return "terraform"
+}
+
 func (tf *Terraform) inContext(fetchVersion bool) bool {
 	terraformFolder := filepath.Join(tf.env.Pwd(), ".terraform")
 
@@ -59,7 +91,7 @@ func (tf *Terraform) inContext(fetchVersion bool) bool {
 		return true
 	}
 
-	files := []string{".tf", ".tfplan", ".tfstate"}
+	files := []string{".tf", ".tfplan", ".tfstate", "*.tofu", terragruntFile}
 	if slices.ContainsFunc(files, tf.env.HasFiles) {
 		return true
 	}
@@ -73,7 +105,7 @@ func (tf *Terraform) inContext(fetchVersion bool) bool {
 }
 
 func (tf *Terraform) setVersionFromTfFiles() error {
-	files := []string{"versions.tf", "main.tf"}
+	files := []string{"versions.tf", "main.tf", "versions.tofu", "main.tofu"}
 	for _, file := range files {
 		if !tf.env.HasFiles(file) {
 			continue
@@ -0,0 +1,299 @@
+package segments
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+const (
+	// PlanFiles are the file patterns of saved plans, like *.tfplan
+	PlanFiles properties.Property = "plan_files"
+
+	terragruntFile = "terragrunt.hcl"
+	lockFile       = ".terraform.lock.hcl"
+)
+
+// TerraformProvider is a provider pinned in .terraform.lock.hcl.
+type TerraformProvider struct {
+	// Source is the full address, like registry.terraform.io/hashicorp/aws
+	Source  string
+	Name    string
+	Version string
+}
+
+type terraformLockFile struct {
+	Providers []struct {
+		Remain  hcl.Body `hcl:",remain"`
+		Source  string   `hcl:"source,label"`
+		Version string   `hcl:"version"`
+	} `hcl:"provider,block"`
+}
+
+// terraformBackendState is the state terraform init writes to .terraform/terraform.tfstate.
+type terraformBackendState struct {
+	Backend *struct {
+		Type   string `json:"type"`
+		Config struct {
+			Workspaces   json.RawMessage `json:"workspaces"`
+			Organization string          `json:"organization"`
+		} `json:"config"`
+	} `json:"backend"`
+}
+
+type terraformRemoteWorkspace struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
+func (tf *Terraform) setProviders() {
+	if !tf.env.HasFiles(lockFile) {
+		return
+	}
+
+	parser := hclparse.NewParser()
+	file, diags := parser.ParseHCL([]byte(tf.env.FileContent(lockFile)), lockFile)
+	if diags.HasErrors() {
+		log.Error(diags)
+		return
+	}
+
+	var lock terraformLockFile
+	if diags = gohcl.DecodeBody(file.Body, nil, &lock); diags.HasErrors() {
+		log.Error(diags)
+		return
+	}
+
+	for _, provider := range lock.Providers {
+		tf.Providers = append(tf.Providers, &TerraformProvider{
+			Source:  provider.Source,
+			Name:    filepath.Base(provider.Source),
+			Version: provider.Version,
+		})
+	}
+}
+
+// setTool detects OpenTofu from the command, or from the registry the providers are locked from.
+func (tf *Terraform) setTool(cmd string) {
+	tf.Tool = "terraform"
+
+	if strings.TrimSuffix(filepath.Base(cmd), ".exe") == "tofu" {
+		tf.Tool = "opentofu"
+		return
+	}
+
+	for _, provider := range tf.Providers {
+		if strings.HasPrefix(provider.Source, "registry.opentofu.org/") {
+			tf.Tool = "opentofu"
+			return
+		}
+	}
+}
+
+func (tf *Terraform) setBackend() {
+	folder := filepath.Join(tf.env.Pwd(), ".terraform")
+	if !tf.env.HasFilesInDir(folder, "terraform.tfstate") {
+		return
+	}
+
+	var state terraformBackendState
+	if err := json.Unmarshal([]byte(tf.env.FileContent(filepath.Join(folder, "terraform.tfstate"))), &state); err != nil {
+		log.Error(err)
+		return
+	}
+
+	if state.Backend == nil {
+		return
+	}
+
+	tf.Backend = state.Backend.Type
+	tf.Organization = state.Backend.Config.Organization
+
+	// the remote backend stores a list of workspaces, the cloud block a single one
+	var workspaces []terraformRemoteWorkspace
+	if err := json.Unmarshal(state.Backend.Config.Workspaces, &workspaces); err != nil {
+		var workspace terraformRemoteWorkspace
+		if err := json.Unmarshal(state.Backend.Config.Workspaces, &workspace); err != nil {
+			return
+		}
+
+		workspaces = append(workspaces, workspace)
+	}
+
+	if len(workspaces) == 0 {
+		return
+	}
+
+	switch workspace := workspaces[0]; {
+	case len(workspace.Name) != 0:
+		tf.RemoteWorkspace = workspace.Name
+	case len(workspace.Prefix) != 0:
+		tf.RemoteWorkspace = workspace.Prefix + tf.WorkspaceName
+	case tf.Backend == "cloud":
+		// workspaces selected by tags map to the local workspace name
+		tf.RemoteWorkspace = tf.WorkspaceName
+	}
+}
+
+// setPlan finds the most recent saved plan, which is stale when a configuration file changed after it.
+func (tf *Terraform) setPlan() {
+	patterns := tf.props.GetStringArray(PlanFiles, []string{"*.tfplan", "tfplan"})
+
+	var planTime, configTime time.Time
+
+	for _, entry := range tf.env.LsDir(tf.env.Pwd()) {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		name := entry.Name()
+
+		if matchesOneOf(name, patterns) {
+			if info.ModTime().After(planTime) {
+				tf.PlanFile = name
+				planTime = info.ModTime()
+			}
+
+			continue
+		}
+
+		if matchesOneOf(name, []string{"*.tf", "*.tofu", "*.tfvars", "*.tfvars.json", lockFile, terragruntFile}) && info.ModTime().After(configTime) {
+			configTime = info.ModTime()
+		}
+	}
+
+	tf.PlanStale = len(tf.PlanFile) != 0 && configTime.After(planTime)
+}
+
+func matchesOneOf(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if match, _ := filepath.Match(pattern, name); match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setEnvironment detects the environment from the variables, the Terragrunt env.hcl or the var file in use.
+func (tf *Terraform) setEnvironment() {
+	tf.Terragrunt = tf.env.HasFiles(terragruntFile)
+
+	for _, key := range []string{"TF_VAR_environment", "TF_VAR_env"} {
+		if tf.Environment = tf.env.Getenv(key); len(tf.Environment) != 0 {
+			return
+		}
+	}
+
+	if tf.Terragrunt {
+		if tf.Environment = tf.terragruntEnvironment(); len(tf.Environment) != 0 {
+			return
+		}
+	}
+
+	for _, key := range []string{"TF_CLI_ARGS_plan", "TF_CLI_ARGS_apply", "TF_CLI_ARGS"} {
+		if tf.Environment = varFileEnvironment(tf.env.Getenv(key)); len(tf.Environment) != 0 {
+			return
+		}
+	}
+
+	tf.Environment = tf.tfvarsEnvironment()
+}
+
+// terragruntEnvironment reads the environment local of the env.hcl file in the current or parent folders,
+// as in the Terragrunt reference architecture. It falls back to the name of the folder holding the file.
+func (tf *Terraform) terragruntEnvironment() string {
+	file, err := tf.env.HasParentFilePath("env.hcl", false)
+	if err != nil {
+		return ""
+	}
+
+	environment := filepath.Base(file.ParentFolder)
+
+	parser := hclparse.NewParser()
+	hclFile, diags := parser.ParseHCL([]byte(tf.env.FileContent(file.Path)), file.Path)
+	if diags.HasErrors() {
+		return environment
+	}
+
+	var config struct {
+		Locals *struct {
+			Remain hcl.Body `hcl:",remain"`
+		} `hcl:"locals,block"`
+		Remain hcl.Body `hcl:",remain"`
+	}
+
+	if diags = gohcl.DecodeBody(hclFile.Body, nil, &config); diags.HasErrors() || config.Locals == nil {
+		return environment
+	}
+
+	attributes, _ := config.Locals.Remain.JustAttributes()
+
+	for _, name := range []string{"environment", "env"} {
+		attribute, ok := attributes[name]
+		if !ok {
+			continue
+		}
+
+		var value string
+		if diags := gohcl.DecodeExpression(attribute.Expr, nil, &value); !diags.HasErrors() && len(value) != 0 {
+			return value
+		}
+	}
+
+	return environment
+}
+
+// varFileEnvironment returns the name of the var file passed in TF_CLI_ARGS, like prod for -var-file=envs/prod.tfvars.
+func varFileEnvironment(args string) string {
+	for arg := range strings.FieldsSeq(args) {
+		if file, found := strings.CutPrefix(strings.TrimLeft(arg, "-"), "var-file="); found {
+			return tfvarsName(strings.Trim(file, `"'`))
+		}
+	}
+
+	return ""
+}
+
+// tfvarsEnvironment returns the name of the only var file in the current folder which isn't loaded automatically.
+func (tf *Terraform) tfvarsEnvironment() string {
+	var environment string
+
+	for _, entry := range tf.env.LsDir(tf.env.Pwd()) {
+		name := entry.Name()
+		if entry.IsDir() || !matchesOneOf(name, []string{"*.tfvars", "*.tfvars.json"}) {
+			continue
+		}
+
+		if strings.HasPrefix(name, "terraform.tfvars") || strings.Contains(name, ".auto.tfvars") {
+			continue
+		}
+
+		if len(environment) != 0 {
+			return ""
+		}
+
+		environment = tfvarsName(name)
+	}
+
+	return environment
+}
+
+func tfvarsName(file string) string {
+	name := filepath.Base(file)
+	name = strings.TrimSuffix(name, ".json")
+	return strings.TrimSuffix(name, ".tfvars")
+}
@@ -1,13 +1,18 @@
 package segments
 
 import (
+	"io/fs"
 	"os"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
 	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
 
 	"github.com/stretchr/testify/assert"
+	testify_ "github.com/stretchr/testify/mock"
 )
 
 func TestTerraform(t *testing.T) {
@@ -85,6 +90,7 @@ func TestTerraform(t *testing.T) {
 		env := new(mock.Environment)
 
 		env.On("HasCommand", "terraform").Return(tc.HasTfCommand)
+		env.On("HasCommand", "tofu").Return(false)
 		env.On("HasFolder", ".terraform").Return(tc.HasTfFolder)
 		env.On("HasFiles", ".tf").Return(tc.HasTfFiles)
 		env.On("HasFiles", ".tfplan").Return(tc.HasTfFiles)
@@ -94,6 +100,10 @@ func TestTerraform(t *testing.T) {
 		env.On("HasFiles", "versions.tf").Return(tc.HasTfVersionFiles)
 		env.On("HasFiles", "main.tf").Return(tc.HasTfVersionFiles)
 		env.On("HasFiles", "terraform.tfstate").Return(tc.HasTfStateFile)
+		env.On("HasFiles", testify_.Anything).Return(false)
+		env.On("HasFilesInDir", ".terraform", "terraform.tfstate").Return(false)
+		env.On("LsDir", "").Return([]fs.DirEntry{})
+		env.On("Getenv", testify_.Anything).Return("")
 		if tc.HasTfVersionFiles {
 			content, _ := os.ReadFile("../test/versions.tf")
 			env.On("FileContent", "versions.tf").Return(string(content))
@@ -119,3 +129,195 @@ func TestTerraform(t *testing.T) {
 		assert.Equal(t, tc.ExpectedString, got, tc.Case)
 	}
 }
+
+func TestTerraformContext(t *testing.T) {
+	lock := `
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:ltxyuBWIy9cq0kIKDJH1jeWJy/y7XJLjS4QrsQK4plA=",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+}
+`
+
+	now := time.Now()
+
+	cases := []struct {
+		Env                     map[string]string
+		Files                   map[string]time.Time
+		Case                    string
+		Command                 string
+		Lock                    string
+		BackendState            string
+		EnvHCL                  string
+		ExpectedTool            string
+		ExpectedBackend         string
+		ExpectedOrganization    string
+		ExpectedRemoteWorkspace string
+		ExpectedPlanFile        string
+		ExpectedEnvironment     string
+		ExpectedProviders       []*TerraformProvider
+		ExpectedPlanStale       bool
+		ExpectedTerragrunt      bool
+	}{
+		{Case: "Empty", ExpectedTool: "terraform"},
+		{
+			Case:         "Providers",
+			Lock:         lock,
+			ExpectedTool: "terraform",
+			ExpectedProviders: []*TerraformProvider{
+				{Source: "registry.terraform.io/hashicorp/aws", Name: "aws", Version: "5.31.0"},
+				{Source: "registry.terraform.io/hashicorp/random", Name: "random", Version: "3.6.0"},
+			},
+		},
+		{
+			Case:         "OpenTofu providers",
+			Lock:         `provider "registry.opentofu.org/hashicorp/aws" { version = "5.31.0" }`,
+			ExpectedTool: "opentofu",
+			ExpectedProviders: []*TerraformProvider{
+				{Source: "registry.opentofu.org/hashicorp/aws", Name: "aws", Version: "5.31.0"},
+			},
+		},
+		{Case: "OpenTofu command", Command: "tofu", ExpectedTool: "opentofu"},
+		{
+			Case:            "S3 backend",
+			BackendState:    `{"version":3,"backend":{"type":"s3","config":{"bucket":"posh-state","key":"app.tfstate"}}}`,
+			ExpectedTool:    "terraform",
+			ExpectedBackend: "s3",
+		},
+		{
+			Case:                    "Remote backend with prefix",
+			BackendState:            `{"version":3,"backend":{"type":"remote","config":{"organization":"posh","workspaces":[{"name":null,"prefix":"app-"}]}}}`,
+			ExpectedTool:            "terraform",
+			ExpectedBackend:         "remote",
+			ExpectedOrganization:    "posh",
+			ExpectedRemoteWorkspace: "app-default",
+		},
+		{
+			Case:                    "Cloud block",
+			BackendState:            `{"version":3,"backend":{"type":"cloud","config":{"organization":"posh","workspaces":{"name":"app-prod","tags":null}}}}`,
+			ExpectedTool:            "terraform",
+			ExpectedBackend:         "cloud",
+			ExpectedOrganization:    "posh",
+			ExpectedRemoteWorkspace: "app-prod",
+		},
+		{
+			Case:             "Plan",
+			Files:            map[string]time.Time{"main.tf": now.Add(-time.Hour), "old.tfplan": now.Add(-2 * time.Hour), "tfplan": now},
+			ExpectedTool:     "terraform",
+			ExpectedPlanFile: "tfplan",
+		},
+		{
+			Case:              "Stale plan",
+			Files:             map[string]time.Time{"main.tf": now, "tfplan": now.Add(-time.Hour)},
+			ExpectedTool:      "terraform",
+			ExpectedPlanFile:  "tfplan",
+			ExpectedPlanStale: true,
+		},
+		{
+			Case:                "Environment variable",
+			Env:                 map[string]string{"TF_VAR_env": "staging"},
+			Files:               map[string]time.Time{"prod.tfvars": now},
+			ExpectedTool:        "terraform",
+			ExpectedEnvironment: "staging",
+		},
+		{
+			Case:                "Var file argument",
+			Env:                 map[string]string{"TF_CLI_ARGS_plan": "-lock=false -var-file=envs/qa.tfvars"},
+			ExpectedTool:        "terraform",
+			ExpectedEnvironment: "qa",
+		},
+		{
+			Case:                "Single var file",
+			Files:               map[string]time.Time{"terraform.tfvars": now, "common.auto.tfvars": now, "prod.tfvars": now},
+			ExpectedTool:        "terraform",
+			ExpectedEnvironment: "prod",
+		},
+		{
+			Case:         "Several var files",
+			Files:        map[string]time.Time{"dev.tfvars": now, "prod.tfvars": now},
+			ExpectedTool: "terraform",
+		},
+		{
+			Case:                "Terragrunt",
+			EnvHCL:              "locals {\n  environment = \"prod\"\n  region = \"eu-west-1\"\n}\n",
+			ExpectedTool:        "terraform",
+			ExpectedEnvironment: "prod",
+			ExpectedTerragrunt:  true,
+		},
+		{
+			Case:                "Terragrunt without locals",
+			EnvHCL:              "inputs = {}\n",
+			ExpectedTool:        "terraform",
+			ExpectedEnvironment: "live-prod",
+			ExpectedTerragrunt:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+
+		command := tc.Command
+		if len(command) == 0 {
+			command = "terraform"
+		}
+
+		env.On("HasCommand", command).Return(true)
+		env.On("Pwd").Return("/posh")
+		env.On("HasFolder", "/posh/.terraform").Return(true)
+		env.On("RunCommand", command, []string{"workspace", "show"}).Return("default", nil)
+
+		env.On("HasFiles", ".terraform.lock.hcl").Return(len(tc.Lock) != 0)
+		env.On("FileContent", ".terraform.lock.hcl").Return(tc.Lock)
+
+		env.On("HasFilesInDir", "/posh/.terraform", "terraform.tfstate").Return(len(tc.BackendState) != 0)
+		env.On("FileContent", "/posh/.terraform/terraform.tfstate").Return(tc.BackendState)
+
+		env.On("HasFiles", "terragrunt.hcl").Return(len(tc.EnvHCL) != 0)
+		env.On("HasParentFilePath", "env.hcl", false).Return(&runtime.FileInfo{Path: "/live-prod/env.hcl", ParentFolder: "/live-prod"}, nil)
+		env.On("FileContent", "/live-prod/env.hcl").Return(tc.EnvHCL)
+
+		files := fstest.MapFS{}
+		for name, modTime := range tc.Files {
+			files[name] = &fstest.MapFile{ModTime: modTime}
+		}
+
+		var entries []fs.DirEntry
+		for name := range tc.Files {
+			info, _ := fs.Stat(files, name)
+			entries = append(entries, &MockDirEntry{name: name, fileInfo: info})
+		}
+
+		env.On("LsDir", "/posh").Return(entries)
+
+		for key, value := range tc.Env {
+			env.On("Getenv", key).Return(value)
+		}
+
+		env.On("Getenv", testify_.Anything).Return("")
+
+		props := properties.Map{}
+		if len(tc.Command) != 0 {
+			props[Command] = tc.Command
+		}
+
+		tf := &Terraform{}
+		tf.Init(props, env)
+
+		assert.True(t, tf.Enabled(), tc.Case)
+		assert.Equal(t, tc.ExpectedTool, tf.Tool, tc.Case)
+		assert.Equal(t, tc.ExpectedProviders, tf.Providers, tc.Case)
+		assert.Equal(t, tc.ExpectedBackend, tf.Backend, tc.Case)
+		assert.Equal(t, tc.ExpectedOrganization, tf.Organization, tc.Case)
+		assert.Equal(t, tc.ExpectedRemoteWorkspace, tf.RemoteWorkspace, tc.Case)
+		assert.Equal(t, tc.ExpectedPlanFile, tf.PlanFile, tc.Case)
+		assert.Equal(t, tc.ExpectedPlanStale, tf.PlanStale, tc.Case)
+		assert.Equal(t, tc.ExpectedEnvironment, tf.Environment, tc.Case)
+		assert.Equal(t, tc.ExpectedTerragrunt, tf.Terragrunt, tc.Case)
+	}
+}
@@ -0,0 +1,358 @@
+package segments
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+	"github.com/jandedobbeleer/oh-my-posh/src/regex"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+const (
+	toolVersionsFile = ".tool-versions"
+
+	toolchainVersionRegex = `^v?(?P<version>(?P<major>[0-9]+)(?:\.(?P<minor>[0-9]+))?(?:\.(?P<patch>[0-9]+))?(?:-?(?P<prerelease>(?:alpha|beta|rc|dev)[0-9]*|[ab][0-9]+))?)`
+)
+
+// toolchain describes where version managers keep the state of a language,
+// which allows resolving the active version without executing a binary.
+// Paths support a leading ~ for the home folder or $VARIABLE, they are skipped when the variable is not set.
+type toolchain struct {
+	// resolve finds the toolchain from the language's own state, like an active virtual env
+	resolve func() (*resolvedToolchain, bool)
+	// tools are the names of the plugin in asdf and mise
+	tools []string
+	// active are environment variables pointing to the toolchain in use, or its bin folder
+	active []string
+	// env are environment variables holding the version to use
+	env []string
+	// files hold the version to use for the folder they're in
+	files []string
+	// globals hold the version to use when no file is found
+	globals []string
+	// installs are the folders holding a folder per installed toolchain
+	installs []string
+}
+
+// resolvedToolchain is the toolchain in use, version is empty when the folder name isn't a version.
+type resolvedToolchain struct {
+	path    string
+	version string
+}
+
+// resolveToolchain finds the toolchain selected by the version manager.
+// The toolchain activated in the shell wins over the version set in the environment,
+// which wins over the nearest version file and finally the global version.
+func (l *Language) resolveToolchain() (*resolvedToolchain, bool) {
+	if l.toolchain == nil {
+		return nil, false
+	}
+
+	if l.toolchain.resolve != nil {
+		return l.toolchain.resolve()
+	}
+
+	for _, name := range l.toolchain.active {
+		value := l.env.Getenv(name)
+		if len(value) == 0 {
+			continue
+		}
+
+		if filepath.Base(value) == "bin" {
+			value = filepath.Dir(value)
+		}
+
+		if target, err := l.env.ResolveSymlink(value); err == nil && len(target) != 0 {
+			value = target
+		}
+
+		log.Debugf("active %s toolchain: %s", l.name, value)
+		return &resolvedToolchain{path: value, version: l.toolchain.folderVersion(filepath.Base(value))}, true
+	}
+
+	spec := l.toolchainSpec()
+	if len(spec) == 0 {
+		return nil, false
+	}
+
+	log.Debugf("%s version from version manager: %s", l.name, spec)
+
+	return l.installedToolchain(spec)
+}
+
+// toolchainSpec returns the version requested by the version manager, like 20 or 3.12.1.
+func (l *Language) toolchainSpec() string {
+	envVars := slices.Clone(l.toolchain.env)
+	for _, tool := range l.toolchain.tools {
+		tool = strings.ToUpper(tool)
+		envVars = append(envVars, "ASDF_"+tool+"_VERSION", "MISE_"+tool+"_VERSION")
+	}
+
+	for _, name := range envVars {
+		if value := l.env.Getenv(name); len(value) != 0 {
+			return value
+		}
+	}
+
+	files := l.toolchain.files
+	if len(l.toolchain.tools) != 0 {
+		files = append(slices.Clone(files), toolVersionsFile, "mise.toml", ".mise.toml")
+	}
+
+	// the version file closest to the current folder wins
+	var spec, folder string
+	for _, file := range files {
+		info, err := l.env.HasParentFilePath(file, false)
+		if err != nil || len(info.ParentFolder) <= len(folder) {
+			continue
+		}
+
+		if value := l.parseToolchainSpec(file, l.env.FileContent(info.Path)); len(value) != 0 {
+			spec, folder = value, info.ParentFolder
+		}
+	}
+
+	if len(spec) != 0 {
+		return spec
+	}
+
+	globals := l.toolchain.globals
+	if len(l.toolchain.tools) != 0 {
+		globals = append(slices.Clone(globals), "$MISE_CONFIG_DIR/config.toml", "~/.config/mise/config.toml", "~/"+toolVersionsFile)
+	}
+
+	for _, global := range l.expandToolchainPaths(globals) {
+		if !l.env.HasFilesInDir(filepath.Dir(global), filepath.Base(global)) {
+			continue
+		}
+
+		if value := l.parseToolchainSpec(filepath.Base(global), l.env.FileContent(global)); len(value) != 0 {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func (l *Language) parseToolchainSpec(file, content string) string {
+	switch file {
+	case toolVersionsFile:
+		for line := range strings.SplitSeq(content, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) > 1 && slices.Contains(l.toolchain.tools, fields[0]) {
+				return fields[1]
+			}
+		}
+	case "mise.toml", ".mise.toml", "config.toml":
+		var config struct {
+			Tools map[string]any `toml:"tools"`
+		}
+
+		if err := toml.Unmarshal([]byte(content), &config); err != nil {
+			log.Error(err)
+			return ""
+		}
+
+		for _, tool := range l.toolchain.tools {
+			if version := miseToolVersion(config.Tools[tool]); len(version) != 0 {
+				return version
+			}
+		}
+	case "rust-toolchain.toml", "rust-toolchain":
+		var config struct {
+			Toolchain struct {
+				Channel string `toml:"channel"`
+			} `toml:"toolchain"`
+		}
+
+		if err := toml.Unmarshal([]byte(content), &config); err == nil {
+			return config.Toolchain.Channel
+		}
+
+		// the legacy file only holds the toolchain name
+		return firstLine(content)
+	case "settings.toml":
+		var settings struct {
+			Overrides        map[string]string `toml:"overrides"`
+			DefaultToolchain string            `toml:"default_toolchain"`
+		}
+
+		if err := toml.Unmarshal([]byte(content), &settings); err != nil {
+			log.Error(err)
+			return ""
+		}
+
+		// rustup override set, the most specific folder wins
+		var folder, spec string
+		pwd := l.env.Pwd()
+		for dir, toolchain := range settings.Overrides {
+			if strings.HasPrefix(pwd, dir) && len(dir) > len(folder) {
+				folder, spec = dir, toolchain
+			}
+		}
+
+		if len(spec) != 0 {
+			return spec
+		}
+
+		return settings.DefaultToolchain
+	case ".sdkmanrc":
+		for line := range strings.SplitSeq(content, "\n") {
+			key, value, found := strings.Cut(line, "=")
+			if found && slices.Contains(l.toolchain.tools, strings.TrimSpace(key)) {
+				return strings.TrimSpace(value)
+			}
+		}
+	default:
+		return firstLine(content)
+	}
+
+	return ""
+}
+
+// installedToolchain finds the installed toolchain best matching spec.
+func (l *Language) installedToolchain(spec string) (*resolvedToolchain, bool) {
+	installs := slices.Clone(l.toolchain.installs)
+	for _, tool := range l.toolchain.tools {
+		installs = append(installs,
+			"$ASDF_DATA_DIR/installs/"+tool,
+			"~/.asdf/installs/"+tool,
+			"$MISE_DATA_DIR/installs/"+tool,
+			"~/.local/share/mise/installs/"+tool,
+		)
+	}
+
+	spec = l.toolchain.folderName(spec)
+
+	for _, dir := range l.expandToolchainPaths(installs) {
+		var match, matchVersion string
+
+		for _, entry := range l.env.LsDir(dir) {
+			name := l.toolchain.folderName(entry.Name())
+			if name != spec && !strings.HasPrefix(name, spec+".") && !strings.HasPrefix(name, spec+"-") {
+				continue
+			}
+
+			if len(match) == 0 || compareVersions(name, matchVersion) > 0 {
+				match, matchVersion = entry.Name(), name
+			}
+		}
+
+		if len(match) == 0 {
+			continue
+		}
+
+		return &resolvedToolchain{path: filepath.Join(dir, match), version: l.toolchain.folderVersion(match)}, true
+	}
+
+	return nil, false
+}
+
+func (l *Language) expandToolchainPaths(paths []string) []string {
+	expanded := make([]string, 0, len(paths))
+
+	for _, dir := range paths {
+		switch {
+		case strings.HasPrefix(dir, "~/"):
+			dir = filepath.Join(l.env.Home(), dir[2:])
+		case strings.HasPrefix(dir, "$"):
+			name, rest, _ := strings.Cut(dir[1:], "/")
+			value := l.env.Getenv(name)
+			if len(value) == 0 {
+				continue
+			}
+
+			dir = filepath.Join(value, rest)
+		}
+
+		if !slices.Contains(expanded, dir) {
+			expanded = append(expanded, dir)
+		}
+	}
+
+	return expanded
+}
+
+// folderName strips the decoration version managers add to a version, like v20.1.0 or ruby-3.3.0.
+func (t *toolchain) folderName(name string) string {
+	for _, tool := range t.tools {
+		name = strings.TrimPrefix(name, tool+"-")
+	}
+
+	return strings.TrimPrefix(name, "v")
+}
+
+// folderVersion returns the version in the name of a toolchain folder, when there is one.
+func (t *toolchain) folderVersion(name string) string {
+	values := regex.FindNamedRegexMatch(toolchainVersionRegex, t.folderName(name))
+	return values["version"]
+}
+
+func (l *Language) setToolchainVersion(toolchain *resolvedToolchain) bool {
+	if len(toolchain.version) == 0 {
+		return false
+	}
+
+	command := &cmd{regex: toolchainVersionRegex}
+	version, err := command.parse(toolchain.version)
+	if err != nil {
+		return false
+	}
+
+	l.Version = *version
+	l.buildVersionURL()
+
+	for _, command := range l.commands {
+		if len(command.executable) != 0 {
+			l.Executable = command.executable
+			break
+		}
+	}
+
+	return true
+}
+
+func miseToolVersion(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case []any:
+		if len(value) != 0 {
+			return miseToolVersion(value[0])
+		}
+	case map[string]any:
+		return miseToolVersion(value["version"])
+	}
+
+	return ""
+}
+
+func firstLine(content string) string {
+	for line := range strings.SplitSeq(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) != 0 && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// compareVersions compares the numeric parts of two versions, 1.10.0 is newer than 1.9.3.
+func compareVersions(a, b string) int {
+	numbers := func(version string) []int {
+		var parts []int
+		for field := range strings.FieldsFuncSeq(version, func(r rune) bool { return r < '0' || r > '9' }) {
+			number, _ := strconv.Atoi(field)
+			parts = append(parts, number)
+		}
+
+		return parts
+	}
+
+	return slices.Compare(numbers(a), numbers(b))
+}
@@ -0,0 +1,304 @@
+package segments
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+	"github.com/jandedobbeleer/oh-my-posh/src/template"
+
+	"github.com/stretchr/testify/assert"
+	testify_ "github.com/stretchr/testify/mock"
+)
+
+// mockNoToolchain makes every version manager lookup come up empty,
+// it has to be called after setting up the specific expectations.
+func mockNoToolchain(env *mock.Environment) {
+	env.On("Getenv", testify_.Anything).Return("")
+	env.On("HasParentFilePath", testify_.Anything, false).Return(&runtime.FileInfo{}, errors.New("no match"))
+	env.On("HasFilesInDir", testify_.Anything, testify_.Anything).Return(false)
+	env.On("LsDir", testify_.Anything).Return([]fs.DirEntry{})
+	env.On("ResolveSymlink", testify_.Anything).Return("", errors.New("no symlink"))
+}
+
+func TestResolveToolchain(t *testing.T) {
+	nodeInstalls := []fs.DirEntry{
+		&MockDirEntry{name: "v18.20.1", isDir: true},
+		&MockDirEntry{name: "v20.9.0", isDir: true},
+		&MockDirEntry{name: "v20.11.0", isDir: true},
+	}
+
+	cases := []struct {
+		Env             map[string]string
+		Files           map[string]string
+		Installs        map[string][]fs.DirEntry
+		Case            string
+		ExpectedPath    string
+		ExpectedVersion string
+		Toolchain       toolchain
+		Resolved        bool
+	}{
+		{
+			Case:      "Nothing configured",
+			Toolchain: toolchain{tools: []string{"nodejs"}, files: []string{".nvmrc"}},
+		},
+		{
+			Case:            "Active nvm",
+			Toolchain:       toolchain{active: []string{"NVM_BIN"}},
+			Env:             map[string]string{"NVM_BIN": "/home/user/.nvm/versions/node/v20.11.0/bin"},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.nvm/versions/node/v20.11.0",
+			ExpectedVersion: "20.11.0",
+		},
+		{
+			Case:            "Nvmrc picks the highest matching install",
+			Toolchain:       toolchain{files: []string{".nvmrc"}, installs: []string{"~/.nvm/versions/node"}},
+			Files:           map[string]string{"/home/user/project/.nvmrc": "v20\n"},
+			Installs:        map[string][]fs.DirEntry{"/home/user/.nvm/versions/node": nodeInstalls},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.nvm/versions/node/v20.11.0",
+			ExpectedVersion: "20.11.0",
+		},
+		{
+			Case:      "Version not installed",
+			Toolchain: toolchain{files: []string{".nvmrc"}, installs: []string{"~/.nvm/versions/node"}},
+			Files:     map[string]string{"/home/user/project/.nvmrc": "22"},
+			Installs:  map[string][]fs.DirEntry{"/home/user/.nvm/versions/node": nodeInstalls},
+		},
+		{
+			Case:      "Tool versions",
+			Toolchain: toolchain{tools: []string{"python"}, files: []string{".python-version"}},
+			Files: map[string]string{
+				"/home/user/project/.tool-versions": "nodejs 20.11.0\npython 3.12.1 3.11.7\n",
+			},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.asdf/installs/python": {&MockDirEntry{name: "3.11.7"}, &MockDirEntry{name: "3.12.1"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.asdf/installs/python/3.12.1",
+			ExpectedVersion: "3.12.1",
+		},
+		{
+			Case:      "Mise config",
+			Toolchain: toolchain{tools: []string{"go"}},
+			Files:     map[string]string{"/home/user/project/mise.toml": "[tools]\ngo = \"1.22\"\nnode = \"20\"\n"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.local/share/mise/installs/go": {&MockDirEntry{name: "1.21.6"}, &MockDirEntry{name: "1.22.0"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.local/share/mise/installs/go/1.22.0",
+			ExpectedVersion: "1.22.0",
+		},
+		{
+			Case:      "Environment wins over files",
+			Toolchain: toolchain{env: []string{"PYENV_VERSION"}, files: []string{".python-version"}, installs: []string{"$PYENV_ROOT/versions"}},
+			Env:       map[string]string{"PYENV_VERSION": "3.11", "PYENV_ROOT": "/opt/pyenv"},
+			Files:     map[string]string{"/home/user/project/.python-version": "3.12.1"},
+			Installs: map[string][]fs.DirEntry{
+				"/opt/pyenv/versions": {&MockDirEntry{name: "3.11.7"}, &MockDirEntry{name: "3.12.1"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/opt/pyenv/versions/3.11.7",
+			ExpectedVersion: "3.11.7",
+		},
+		{
+			Case:      "Global version",
+			Toolchain: toolchain{files: []string{".ruby-version"}, globals: []string{"~/.rbenv/version"}, installs: []string{"~/.rbenv/versions"}},
+			Files:     map[string]string{"/home/user/.rbenv/version": "3.3.0"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.rbenv/versions": {&MockDirEntry{name: "3.3.0"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.rbenv/versions/3.3.0",
+			ExpectedVersion: "3.3.0",
+		},
+		{
+			Case:      "Chruby folder names",
+			Toolchain: toolchain{tools: []string{"ruby"}, files: []string{".ruby-version"}, installs: []string{"~/.rubies"}},
+			Files:     map[string]string{"/home/user/project/.ruby-version": "ruby-3.2"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.rubies": {&MockDirEntry{name: "ruby-3.2.2"}, &MockDirEntry{name: "ruby-3.3.0"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.rubies/ruby-3.2.2",
+			ExpectedVersion: "3.2.2",
+		},
+		{
+			Case:      "Rust channel",
+			Toolchain: toolchain{files: []string{"rust-toolchain.toml"}, installs: []string{"~/.rustup/toolchains"}},
+			Files:     map[string]string{"/home/user/project/rust-toolchain.toml": "[toolchain]\nchannel = \"stable\"\n"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.rustup/toolchains": {&MockDirEntry{name: "nightly-x86_64-unknown-linux-gnu"}, &MockDirEntry{name: "stable-x86_64-unknown-linux-gnu"}},
+			},
+			Resolved:     true,
+			ExpectedPath: "/home/user/.rustup/toolchains/stable-x86_64-unknown-linux-gnu",
+		},
+		{
+			Case:      "Rust version with host triple",
+			Toolchain: toolchain{globals: []string{"~/.rustup/settings.toml"}, installs: []string{"~/.rustup/toolchains"}},
+			Files:     map[string]string{"/home/user/.rustup/settings.toml": "default_toolchain = \"1.75\"\n"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.rustup/toolchains": {&MockDirEntry{name: "1.75.0-aarch64-apple-darwin"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.rustup/toolchains/1.75.0-aarch64-apple-darwin",
+			ExpectedVersion: "1.75.0",
+		},
+		{
+			Case:      "Rustup override",
+			Toolchain: toolchain{globals: []string{"~/.rustup/settings.toml"}, installs: []string{"~/.rustup/toolchains"}},
+			Files: map[string]string{
+				"/home/user/.rustup/settings.toml": "default_toolchain = \"stable\"\n[overrides]\n\"/home/user/project\" = \"1.70.0\"\n",
+			},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.rustup/toolchains": {&MockDirEntry{name: "1.70.0-x86_64-unknown-linux-gnu"}, &MockDirEntry{name: "stable-x86_64-unknown-linux-gnu"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.rustup/toolchains/1.70.0-x86_64-unknown-linux-gnu",
+			ExpectedVersion: "1.70.0",
+		},
+		{
+			Case:      "Sdkman",
+			Toolchain: toolchain{tools: []string{"java"}, files: []string{".sdkmanrc"}, installs: []string{"$SDKMAN_DIR/candidates/java"}},
+			Env:       map[string]string{"SDKMAN_DIR": "/home/user/.sdkman"},
+			Files:     map[string]string{"/home/user/project/.sdkmanrc": "# sdk env\njava=21.0.2-tem\n"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.sdkman/candidates/java": {&MockDirEntry{name: "17.0.10-tem"}, &MockDirEntry{name: "21.0.2-tem"}, &MockDirEntry{name: "current"}},
+			},
+			Resolved:        true,
+			ExpectedPath:    "/home/user/.sdkman/candidates/java/21.0.2-tem",
+			ExpectedVersion: "21.0.2",
+		},
+		{
+			Case:      "Pyenv virtualenv",
+			Toolchain: toolchain{files: []string{".python-version"}, installs: []string{"~/.pyenv/versions"}},
+			Files:     map[string]string{"/home/user/project/.python-version": "tools"},
+			Installs: map[string][]fs.DirEntry{
+				"/home/user/.pyenv/versions": {&MockDirEntry{name: "3.12.1"}, &MockDirEntry{name: "tools", fileMode: fs.ModeSymlink}},
+			},
+			Resolved:     true,
+			ExpectedPath: "/home/user/.pyenv/versions/tools",
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Home").Return("/home/user")
+		env.On("Pwd").Return("/home/user/project")
+
+		for key, value := range tc.Env {
+			env.On("Getenv", key).Return(value)
+		}
+
+		for file, content := range tc.Files {
+			dir, name := filepath.Dir(file), filepath.Base(file)
+			env.On("HasParentFilePath", name, false).Return(&runtime.FileInfo{ParentFolder: dir, Path: file}, nil)
+			env.On("HasFilesInDir", dir, name).Return(true)
+			env.On("FileContent", file).Return(content)
+		}
+
+		for dir, entries := range tc.Installs {
+			env.On("LsDir", dir).Return(entries)
+		}
+
+		mockNoToolchain(env)
+
+		l := &Language{toolchain: &tc.Toolchain}
+		l.Init(properties.Map{}, env)
+
+		resolved, ok := l.resolveToolchain()
+		assert.Equal(t, tc.Resolved, ok, tc.Case)
+
+		if !tc.Resolved {
+			continue
+		}
+
+		assert.Equal(t, tc.ExpectedPath, resolved.path, tc.Case)
+		assert.Equal(t, tc.ExpectedVersion, resolved.version, tc.Case)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		Case     string
+		A        string
+		B        string
+		Expected int
+	}{
+		{Case: "Equal", A: "1.2.3", B: "1.2.3", Expected: 0},
+		{Case: "Numeric minor", A: "1.10.0", B: "1.9.3", Expected: 1},
+		{Case: "Shorter", A: "20", B: "20.1.0", Expected: -1},
+		{Case: "Decorated", A: "21.0.2-tem", B: "17.0.10-tem", Expected: 1},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.Expected, compareVersions(tc.A, tc.B), tc.Case)
+	}
+}
+
+func TestToolchainSetVersion(t *testing.T) {
+	cases := []struct {
+		Case               string
+		Active             string
+		ExpectedVersion    string
+		ExpectedExecutable string
+		ExpectedURL        string
+	}{
+		{
+			Case:               "Version from the toolchain folder",
+			Active:             "/home/user/.nvm/versions/node/v20.11.0/bin",
+			ExpectedVersion:    "20.11.0",
+			ExpectedExecutable: "node",
+			ExpectedURL:        "https://nodejs.org/20",
+		},
+		{
+			Case:               "Folder without a version runs the binary",
+			Active:             "/usr/lib/jvm/default",
+			ExpectedVersion:    "18.0.0",
+			ExpectedExecutable: "node",
+			ExpectedURL:        "https://nodejs.org/18",
+		},
+		{
+			Case:               "No toolchain runs the binary",
+			ExpectedVersion:    "18.0.0",
+			ExpectedExecutable: "node",
+			ExpectedURL:        "https://nodejs.org/18",
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+		env.On("Getenv", "NVM_BIN").Return(tc.Active)
+		env.On("HasCommand", "node").Return(true)
+		env.On("RunCommand", "node", []string{"--version"}).Return("v18.0.0", nil)
+		env.On("Shell").Return("foo")
+		mockNoToolchain(env)
+
+		template.Cache = &cache.Template{}
+		template.Init(env, nil, nil)
+
+		l := &Language{
+			name: "node",
+			commands: []*cmd{
+				{
+					executable: "node",
+					args:       []string{"--version"},
+					regex:      `(?:v(?P<version>((?P<major>[0-9]+).(?P<minor>[0-9]+).(?P<patch>[0-9]+))))`,
+				},
+			},
+			versionURLTemplate: "https://nodejs.org/{{ .Major }}",
+			toolchain:          &toolchain{active: []string{"NVM_BIN"}},
+		}
+		l.Init(properties.Map{}, env)
+
+		assert.NoError(t, l.setVersion(), tc.Case)
+		assert.Equal(t, tc.ExpectedVersion, l.Full, tc.Case)
+		assert.Equal(t, tc.ExpectedExecutable, l.Executable, tc.Case)
+		assert.Equal(t, tc.ExpectedURL, l.URL, tc.Case)
+	}
+}
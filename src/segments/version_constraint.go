@@ -0,0 +1,269 @@
+package segments
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/log"
+
+	"github.com/Masterminds/semver/v3"
+	toml "github.com/pelletier/go-toml/v2"
+	"golang.org/x/mod/modfile"
+)
+
+// versionConstraint declares where a language keeps the version a project expects.
+type versionConstraint struct {
+	// parse extracts the constraint from the file, as a semver range like >=1.2.0 <2.0.0
+	parse func(content string) string
+	// file holds the constraint, searched in the current and parent folders
+	file string
+	// source names the setting in the file, used in the reason
+	source string
+}
+
+// checkVersionConstraints sets Mismatch when the version doesn't satisfy one of the constraints.
+func (l *Language) checkVersionConstraints() {
+	if len(l.constraints) == 0 || len(l.Full) == 0 {
+		return
+	}
+
+	version, err := semver.NewVersion(l.Full)
+	if err != nil {
+		log.Debugf("unable to check the constraints of %s: %s", l.Full, err)
+		return
+	}
+
+	// a prerelease, like a nightly toolchain, satisfies the constraints of its release
+	release := semver.New(version.Major(), version.Minor(), version.Patch(), "", "")
+
+	for _, constraint := range l.constraints {
+		file, err := l.env.HasParentFilePath(constraint.file, false)
+		if err != nil {
+			continue
+		}
+
+		expected := strings.TrimSpace(constraint.parse(l.env.FileContent(file.Path)))
+		if len(expected) == 0 {
+			continue
+		}
+
+		constraints, err := semver.NewConstraint(expected)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+
+		if constraints.Check(release) {
+			continue
+		}
+
+		l.Mismatch = true
+		l.Expected = expected
+		l.Reason = fmt.Sprintf("%s in %s requires %s", constraint.source, constraint.file, expected)
+		return
+	}
+}
+
+// minimumVersion is the constraint for settings holding the lowest supported version.
+func minimumVersion(version string) string {
+	if len(version) == 0 {
+		return ""
+	}
+
+	return ">= " + version
+}
+
+func nodeEnginesConstraint(content string) string {
+	var data struct {
+		Engines struct {
+			Node string `json:"node"`
+		} `json:"engines"`
+	}
+
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return ""
+	}
+
+	return data.Engines.Node
+}
+
+func goDirectiveConstraint(content string) string {
+	file, err := modfile.ParseLax("go.mod", []byte(content), nil)
+	if err != nil || file.Go == nil {
+		return ""
+	}
+
+	return minimumVersion(file.Go.Version)
+}
+
+func rustVersionConstraint(content string) string {
+	var data struct {
+		Package struct {
+			RustVersion string `toml:"rust-version"`
+		} `toml:"package"`
+		Workspace struct {
+			Package struct {
+				RustVersion string `toml:"rust-version"`
+			} `toml:"package"`
+		} `toml:"workspace"`
+	}
+
+	if err := toml.Unmarshal([]byte(content), &data); err != nil {
+		return ""
+	}
+
+	if len(data.Package.RustVersion) != 0 {
+		return minimumVersion(data.Package.RustVersion)
+	}
+
+	return minimumVersion(data.Workspace.Package.RustVersion)
+}
+
+func requiresPythonConstraint(content string) string {
+	var data struct {
+		Project struct {
+			RequiresPython string `toml:"requires-python"`
+		} `toml:"project"`
+	}
+
+	if err := toml.Unmarshal([]byte(content), &data); err != nil {
+		return ""
+	}
+
+	return pep440Constraint(data.Project.RequiresPython)
+}
+
+func poetryPythonConstraint(content string) string {
+	var data struct {
+		Tool struct {
+			Poetry struct {
+				Dependencies map[string]any `toml:"dependencies"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}
+
+	if err := toml.Unmarshal([]byte(content), &data); err != nil {
+		return ""
+	}
+
+	// poetry uses the npm style ranges
+	python, _ := data.Tool.Poetry.Dependencies["python"].(string)
+	return python
+}
+
+func uvLockConstraint(content string) string {
+	var data struct {
+		RequiresPython string `toml:"requires-python"`
+	}
+
+	if err := toml.Unmarshal([]byte(content), &data); err != nil {
+		return ""
+	}
+
+	return pep440Constraint(data.RequiresPython)
+}
+
+func poetryLockConstraint(content string) string {
+	var data struct {
+		Metadata struct {
+			PythonVersions string `toml:"python-versions"`
+		} `toml:"metadata"`
+	}
+
+	if err := toml.Unmarshal([]byte(content), &data); err != nil {
+		return ""
+	}
+
+	return data.Metadata.PythonVersions
+}
+
+// pipfileLockConstraint allows every patch of the python_version pipenv locked, like 3.11.
+func pipfileLockConstraint(content string) string {
+	var data struct {
+		Meta struct {
+			Requires struct {
+				PythonVersion string `json:"python_version"`
+			} `json:"requires"`
+		} `json:"_meta"`
+	}
+
+	if err := json.Unmarshal([]byte(content), &data); err != nil || len(data.Meta.Requires.PythonVersion) == 0 {
+		return ""
+	}
+
+	return "~" + data.Meta.Requires.PythonVersion
+}
+
+// pep440Constraint converts a PEP 440 version specifier, like >=3.9,!=3.9.1,~=3.10, to a semver range.
+func pep440Constraint(specifier string) string {
+	if len(specifier) == 0 {
+		return ""
+	}
+
+	var ranges []string
+
+	for clause := range strings.SplitSeq(specifier, ",") {
+		clause = strings.TrimSpace(clause)
+
+		switch {
+		case strings.HasPrefix(clause, "~="):
+			ranges = append(ranges, compatibleRelease(strings.TrimSpace(clause[2:])))
+		case strings.HasPrefix(clause, "==="):
+			ranges = append(ranges, "="+strings.TrimSpace(clause[3:]))
+		case strings.HasPrefix(clause, "=="):
+			ranges = append(ranges, "="+strings.TrimSpace(clause[2:]))
+		case len(clause) != 0:
+			ranges = append(ranges, clause)
+		}
+	}
+
+	return strings.Join(ranges, ", ")
+}
+
+// compatibleRelease converts ~=X.Y to >=X.Y, <X+1 and ~=X.Y.Z to >=X.Y.Z, <X.Y+1.
+func compatibleRelease(version string) string {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return ">=" + version
+	}
+
+	prefix := parts[:len(parts)-2]
+	next, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return ">=" + version
+	}
+
+	upper := strings.Join(append(prefix, strconv.Itoa(next+1)), ".")
+	return fmt.Sprintf(">=%s, <%s", version, upper)
+}
+
+func globalJSONConstraint(content string) string {
+	var data globalJSON
+	if err := json.Unmarshal([]byte(content), &data); err != nil || len(data.Sdk.Version) == 0 {
+		return ""
+	}
+
+	version, err := semver.NewVersion(data.Sdk.Version)
+	if err != nil {
+		return ""
+	}
+
+	// https://learn.microsoft.com/dotnet/core/tools/global-json#rollforward
+	minimum := ">= " + data.Sdk.Version
+	band := version.Patch() / 100
+
+	switch strings.ToLower(data.Sdk.RollForward) {
+	case "disable":
+		return "= " + data.Sdk.Version
+	case "feature", "latestfeature":
+		return fmt.Sprintf("%s, < %d.%d.0", minimum, version.Major(), version.Minor()+1)
+	case "minor", "latestminor":
+		return fmt.Sprintf("%s, < %d.0.0", minimum, version.Major()+1)
+	case "major", "latestmajor":
+		return minimum
+	default:
+		return fmt.Sprintf("%s, < %d.%d.%d", minimum, version.Major(), version.Minor(), (band+1)*100)
+	}
+}
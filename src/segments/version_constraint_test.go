@@ -0,0 +1,173 @@
+package segments
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersionConstraints(t *testing.T) {
+	cases := []struct {
+		Constraint       *versionConstraint
+		Case             string
+		Version          string
+		Content          string
+		ExpectedExpected string
+		ExpectedReason   string
+		ExpectedMismatch bool
+		NoFile           bool
+	}{
+		{
+			Case:       "No file",
+			Version:    "16.0.0",
+			Constraint: &versionConstraint{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+			NoFile:     true,
+		},
+		{
+			Case:       "No engines",
+			Version:    "16.0.0",
+			Constraint: &versionConstraint{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+			Content:    `{"name":"posh"}`,
+		},
+		{
+			Case:       "Engines satisfied",
+			Version:    "20.11.0",
+			Constraint: &versionConstraint{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+			Content:    `{"engines":{"node":"^18.17 || >=20"}}`,
+		},
+		{
+			Case:             "Engines not satisfied",
+			Version:          "16.20.2",
+			Constraint:       &versionConstraint{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+			Content:          `{"engines":{"node":"^18.17 || >=20"}}`,
+			ExpectedMismatch: true,
+			ExpectedExpected: "^18.17 || >=20",
+			ExpectedReason:   "engines.node in package.json requires ^18.17 || >=20",
+		},
+		{
+			Case:             "Go directive",
+			Version:          "1.21.5",
+			Constraint:       &versionConstraint{file: "go.mod", source: "go directive", parse: goDirectiveConstraint},
+			Content:          "module example.com/posh\n\ngo 1.22.1\n",
+			ExpectedMismatch: true,
+			ExpectedExpected: ">= 1.22.1",
+			ExpectedReason:   "go directive in go.mod requires >= 1.22.1",
+		},
+		{
+			Case:       "Rust nightly satisfies its release",
+			Version:    "1.77.0-nightly",
+			Constraint: &versionConstraint{file: "Cargo.toml", source: "rust-version", parse: rustVersionConstraint},
+			Content:    "[package]\nname = \"posh\"\nrust-version = \"1.77\"\n",
+		},
+		{
+			Case:             "Rust workspace version",
+			Version:          "1.70.0",
+			Constraint:       &versionConstraint{file: "Cargo.toml", source: "rust-version", parse: rustVersionConstraint},
+			Content:          "[workspace.package]\nrust-version = \"1.74\"\n",
+			ExpectedMismatch: true,
+			ExpectedExpected: ">= 1.74",
+			ExpectedReason:   "rust-version in Cargo.toml requires >= 1.74",
+		},
+		{
+			Case:             "Requires python",
+			Version:          "3.13.0",
+			Constraint:       &versionConstraint{file: "pyproject.toml", source: "requires-python", parse: requiresPythonConstraint},
+			Content:          "[project]\nrequires-python = \">=3.10,<3.13\"\n",
+			ExpectedMismatch: true,
+			ExpectedExpected: ">=3.10, <3.13",
+			ExpectedReason:   "requires-python in pyproject.toml requires >=3.10, <3.13",
+		},
+		{
+			Case:       "Poetry python",
+			Version:    "3.11.4",
+			Constraint: &versionConstraint{file: "pyproject.toml", source: "tool.poetry.dependencies.python", parse: poetryPythonConstraint},
+			Content:    "[tool.poetry.dependencies]\npython = \"^3.11\"\n",
+		},
+		{
+			Case:             "Global json",
+			Version:          "8.0.204",
+			Constraint:       &versionConstraint{file: "global.json", source: "sdk.version", parse: globalJSONConstraint},
+			Content:          `{"sdk":{"version":"8.0.100"}}`,
+			ExpectedMismatch: true,
+			ExpectedExpected: ">= 8.0.100, < 8.0.200",
+			ExpectedReason:   "sdk.version in global.json requires >= 8.0.100, < 8.0.200",
+		},
+		{
+			Case:       "Invalid constraint",
+			Version:    "1.0.0",
+			Constraint: &versionConstraint{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+			Content:    `{"engines":{"node":"latest"}}`,
+		},
+		{
+			Case:       "Version isn't semver",
+			Version:    "______",
+			Constraint: &versionConstraint{file: "package.json", source: "engines.node", parse: nodeEnginesConstraint},
+			Content:    `{"engines":{"node":">=20"}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		env := new(mock.Environment)
+
+		var err error
+		if tc.NoFile {
+			err = errors.New("no match")
+		}
+
+		env.On("HasParentFilePath", tc.Constraint.file, false).Return(&runtime.FileInfo{Path: "/posh/" + tc.Constraint.file}, err)
+		env.On("FileContent", "/posh/"+tc.Constraint.file).Return(tc.Content)
+
+		l := &Language{constraints: []*versionConstraint{tc.Constraint}}
+		l.Init(properties.Map{}, env)
+		l.Full = tc.Version
+
+		l.checkVersionConstraints()
+
+		assert.Equal(t, tc.ExpectedMismatch, l.Mismatch, tc.Case)
+		assert.Equal(t, tc.ExpectedExpected, l.Expected, tc.Case)
+		assert.Equal(t, tc.ExpectedReason, l.Reason, tc.Case)
+	}
+}
+
+func TestPep440Constraint(t *testing.T) {
+	cases := []struct {
+		Case      string
+		Specifier string
+		Expected  string
+	}{
+		{Case: "Empty"},
+		{Case: "Range", Specifier: ">=3.9,<4", Expected: ">=3.9, <4"},
+		{Case: "Compatible minor", Specifier: "~=3.9", Expected: ">=3.9, <4"},
+		{Case: "Compatible patch", Specifier: "~=3.9.2", Expected: ">=3.9.2, <3.10"},
+		{Case: "Exclusion", Specifier: ">=3.8, !=3.9.1", Expected: ">=3.8, !=3.9.1"},
+		{Case: "Wildcard", Specifier: "==3.11.*", Expected: "=3.11.*"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.Expected, pep440Constraint(tc.Specifier), tc.Case)
+	}
+}
+
+func TestGlobalJSONConstraint(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Content  string
+		Expected string
+	}{
+		{Case: "No version", Content: `{"sdk":{}}`},
+		{Case: "Default roll forward", Content: `{"sdk":{"version":"8.0.303"}}`, Expected: ">= 8.0.303, < 8.0.400"},
+		{Case: "Disabled", Content: `{"sdk":{"version":"8.0.303","rollForward":"disable"}}`, Expected: "= 8.0.303"},
+		{Case: "Latest feature", Content: `{"sdk":{"version":"8.0.100","rollForward":"latestFeature"}}`, Expected: ">= 8.0.100, < 8.1.0"},
+		{Case: "Minor", Content: `{"sdk":{"version":"6.0.100","rollForward":"minor"}}`, Expected: ">= 6.0.100, < 7.0.0"},
+		{Case: "Latest major", Content: `{"sdk":{"version":"6.0.100","rollForward":"latestMajor"}}`, Expected: ">= 6.0.100"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.Expected, globalJSONConstraint(tc.Content), tc.Case)
+	}
+}
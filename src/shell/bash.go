@@ -49,7 +49,7 @@ bleopt prompt_ps1_final='$(
         --shell-version="$BASH_VERSION" \
         --escape=false
 )'`
-	case PromptMark, PoshGit, Azure, LineError, Jobs, Tooltips, Async:
+	case PromptMark, PoshGit, Azure, LineError, Jobs, Tooltips, Async, PWD:
 		fallthrough
 	default:
 		return ""
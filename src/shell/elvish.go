@@ -13,7 +13,15 @@ func (f Features) Elvish() Code {
 		return "$_omp_executable upgrade --auto"
 	case Notice:
 		return "$_omp_executable notice"
-	case PromptMark, RPrompt, PoshGit, Azure, LineError, Jobs, CursorPositioning, Tooltips, Transient, FTCSMarks, Async:
+	case FTCSMarks:
+		return "set _omp_ftcs_marks = $true"
+	case Transient:
+		return "set _omp_transient_prompt = $true"
+	case Tooltips:
+		return "enable_poshtooltips"
+	case PWD:
+		return "set _omp_pwd = $true"
+	case PromptMark, RPrompt, PoshGit, Azure, LineError, Jobs, CursorPositioning, Async:
 		fallthrough
 	default:
 		return ""
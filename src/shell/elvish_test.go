@@ -10,8 +10,12 @@ func TestElvishFeatures(t *testing.T) {
 	got := allFeatures.Lines(ELVISH).String("// these are the features")
 
 	want := `// these are the features
+enable_poshtooltips
+set _omp_transient_prompt = $true
+set _omp_ftcs_marks = $true
 $_omp_executable upgrade --auto
-$_omp_executable notice`
+$_omp_executable notice
+set _omp_pwd = $true`
 
 	assert.Equal(t, want, got)
 }
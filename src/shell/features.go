@@ -11,6 +11,7 @@ const (
 	LineError
 	Tooltips
 	Transient
+	// FTCSMarks enables the shell integration protocol, see PromptMarks.
 	FTCSMarks
 	Upgrade
 	Notice
@@ -18,8 +19,29 @@ const (
 	RPrompt
 	CursorPositioning
 	Async
+	// PWD enables printing the current working directory sequence in the shell scripts
+	// of the shells which can't print it as part of the prompt.
+	PWD
 )
 
+// PromptMarks reports whether the prompt writes the shell integration marks for shell.
+//
+// The shell integration protocol consists of the OSC 133 marks for the prompt start (A),
+// the command start (B), the command execution (C) and the command finished with its exit code (D),
+// the OSC 633 command line (E) and current directory (P;Cwd) properties, and OSC 7.
+// The prompt writes A, B, D and the current directory as part of its output. The command line and C
+// are only known right before a command executes, so every shell script writes those when FTCSMarks is enabled.
+// Shells which can't print escape sequences as part of the prompt write all marks in their script,
+// or use their built-in integration.
+func PromptMarks(shell string) bool {
+	switch shell {
+	case NU, ELVISH, XONSH:
+		return false
+	default:
+		return true
+	}
+}
+
 // getAllFeatures returns all defined feature flags by iterating through bit positions
 func getAllFeatures() []Features {
 	var features []Features
@@ -29,7 +51,7 @@ func getAllFeatures() []Features {
 		feature := Features(1 << i)
 
 		// Stop when we reach a power of 2 greater than our highest defined feature
-		if feature > Async*2 {
+		if feature > PWD*2 {
 			break
 		}
 
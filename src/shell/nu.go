@@ -12,12 +12,18 @@ var nuInit string
 func (f Features) Nu() Code {
 	switch f {
 	case Transient:
-		return `$env.TRANSIENT_PROMPT_COMMAND = {|| _omp_get_prompt transient }`
+		return `$env.TRANSIENT_PROMPT_COMMAND = {|| _omp_get_prompt transient }
+$env.TRANSIENT_PROMPT_COMMAND_RIGHT = {|| '' }`
+	case Tooltips:
+		return "enable_poshtooltips"
 	case Upgrade:
 		return "^$_omp_executable upgrade --auto"
 	case Notice:
 		return "^$_omp_executable notice"
-	case PromptMark, RPrompt, PoshGit, Azure, LineError, Jobs, Tooltips, FTCSMarks, CursorPositioning, Async:
+	case FTCSMarks:
+		// nushell writes the marks using its built-in shell integration
+		return `$env.config = ($env.config | upsert shell_integration.osc133 true | upsert shell_integration.osc633 true | upsert shell_integration.osc7 true)`
+	case PromptMark, RPrompt, PoshGit, Azure, LineError, Jobs, CursorPositioning, Async, PWD:
 		fallthrough
 	default:
 		return ""
@@ -11,7 +11,10 @@ func TestNuFeatures(t *testing.T) {
 	got := allFeatures.Lines(NU).String("// these are the features")
 
 	want := `// these are the features
+enable_poshtooltips
 $env.TRANSIENT_PROMPT_COMMAND = {|| _omp_get_prompt transient }
+$env.TRANSIENT_PROMPT_COMMAND_RIGHT = {|| '' }
+$env.config = ($env.config | upsert shell_integration.osc133 true | upsert shell_integration.osc633 true | upsert shell_integration.osc7 true)
 ^$_omp_executable upgrade --auto
 ^$_omp_executable notice`
 
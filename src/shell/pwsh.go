@@ -24,12 +24,12 @@ func (f Features) Pwsh() Code {
 	case PoshGit:
 		return "$global:_ompPoshGit = $true"
 	case FTCSMarks:
-		return "$global:_ompFTCSMarks = $true"
+		return "Enable-PoshShellIntegration"
 	case Upgrade:
 		return "& $global:_ompExecutable upgrade --auto"
 	case Notice:
 		return "& $global:_ompExecutable notice"
-	case PromptMark, RPrompt, CursorPositioning, Async:
+	case PromptMark, RPrompt, CursorPositioning, Async, PWD:
 		fallthrough
 	default:
 		return ""
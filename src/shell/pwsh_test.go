@@ -7,7 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var allFeatures = Tooltips | LineError | Transient | Jobs | Azure | PoshGit | FTCSMarks | Upgrade | Notice | PromptMark | RPrompt | CursorPositioning
+var allFeatures = Tooltips | LineError | Transient | Jobs | Azure | PoshGit | FTCSMarks | Upgrade | Notice | PromptMark | RPrompt | CursorPositioning | PWD
 
 func TestPwshFeatures(t *testing.T) {
 	got := allFeatures.Lines(PWSH).String("")
@@ -19,7 +19,7 @@ $global:_ompPoshGit = $true
 Enable-PoshLineError
 Enable-PoshTooltips
 Enable-PoshTransientPrompt
-$global:_ompFTCSMarks = $true
+Enable-PoshShellIntegration
 & $global:_ompExecutable upgrade --auto
 & $global:_ompExecutable notice`
 
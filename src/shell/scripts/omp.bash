@@ -57,10 +57,22 @@ function _omp_start_timer() {
 
 function _omp_ftcs_command_start() {
     if [[ $_omp_ftcs_marks == 1 ]]; then
-        printf '\e]133;C\a'
+        local command
+        command=$(HISTTIMEFORMAT='' builtin history 1)
+        # strip the history number
+        command=${command#*[[:digit:]][* ] }
+        printf '\e]633;E;%s\a\e]133;C\a' "$(_omp_escape_property "$command")"
     fi
 }
 
+# escape the value of an OSC 633 property
+function _omp_escape_property() {
+    local value=${1//\\/\\\\}
+    value=${value//;/\\x3b}
+    value=${value//$'\n'/\\x0a}
+    printf '%s' "${value//$'\r'/\\x0d}"
+}
+
 # template function for context loading
 function set_poshcontext() {
     return
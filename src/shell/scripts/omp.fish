@@ -127,7 +127,11 @@ end
 
 function _omp_preexec --on-event fish_preexec
     if test $_omp_ftcs_marks = 1
-        echo -ne "\e]133;C\a"
+        # escape the command line as an OSC 633 property
+        set --local command_line (string join \n -- $argv | string replace --all -- \\ \\\\ | string replace --all -- \; \\x3b | string join \\x0a)
+        echo -ne "\e]633;E;"
+        echo -n -- "$command_line"
+        echo -ne "\a\e]133;C\a"
     end
 end
 
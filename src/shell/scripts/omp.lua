@@ -205,12 +205,21 @@ local function display_cached_prompt()
     cached_prompt.only_use_cache = nil
 end
 
+-- escape the value of an OSC 633 property
+local function escape_property(value)
+    value = string.gsub(value, '\\', '\\\\')
+    value = string.gsub(value, '[%c;]', function(char)
+        return string.format('\\x%02x', string.byte(char))
+    end)
+    return value
+end
+
 local function command_executed_mark(input)
     if string.gsub(input, '^%s*(.-)%s*$', '%1') ~= '' then
         no_exit_code = false
     end
     if ftcs_marks_enabled then
-        clink.print('\x1b]133;C\007', NONL)
+        clink.print('\x1b]633;E;' .. escape_property(input) .. '\007\x1b]133;C\007', NONL)
     end
 end
 
@@ -359,10 +359,6 @@ New-Module -Name "oh-my-posh-core" -ScriptBlock {
                 }
                 finally {
                     & $AcceptLineFunction
-                    if ($global:_ompFTCSMarks -and $executingCommand) {
-                        # Write FTCS_COMMAND_EXECUTED after accepting the input - it should still happen before execution
-                        Write-Host "$([char]27)]133;C$([char]7)" -NoNewline
-                    }
                 }
             }.GetNewClosure()
         }
@@ -408,6 +404,25 @@ New-Module -Name "oh-my-posh-core" -ScriptBlock {
         Set-PSReadLineOption -PromptText $validLine, $errorLine
     }
 
+    function Enable-PoshShellIntegration {
+        $global:_ompFTCSMarks = $true
+
+        if ($script:ConstrainedLanguageMode -or !(Test-Path Function:\PSConsoleHostReadLine)) {
+            return
+        }
+
+        # Write the command line and FTCS_COMMAND_EXECUTED after accepting the input - it should still happen before execution
+        $script:OriginalReadLine = $Function:PSConsoleHostReadLine
+        $Function:global:PSConsoleHostReadLine = {
+            $commandLine = & $script:OriginalReadLine
+            if ($global:_ompFTCSMarks -and $commandLine) {
+                $escaped = $commandLine.Replace('\', '\\').Replace(';', '\x3b').Replace("`n", '\x0a').Replace("`r", '\x0d')
+                Write-Host "$([char]27)]633;E;$escaped$([char]7)$([char]27)]133;C$([char]7)" -NoNewline
+            }
+            $commandLine
+        }
+    }
+
     # perform cleanup on removal so a new initialization in current session works
     if (!$script:ConstrainedLanguageMode) {
         $ExecutionContext.SessionState.Module.OnRemove += {
@@ -415,6 +430,10 @@ New-Module -Name "oh-my-posh-core" -ScriptBlock {
 
             $Function:prompt = $script:OriginalPromptFunction
 
+            if ($script:OriginalReadLine) {
+                $Function:global:PSConsoleHostReadLine = $script:OriginalReadLine
+            }
+
             (Get-PSReadLineOption).ContinuationPrompt = $script:OriginalContinuationPrompt
             (Get-PSReadLineOption).PromptText = $script:OriginalPromptText
 
@@ -443,6 +462,7 @@ New-Module -Name "oh-my-posh-core" -ScriptBlock {
         "Enable-PoshTooltips"
         "Enable-PoshTransientPrompt"
         "Enable-PoshLineError"
+        "Enable-PoshShellIntegration"
         "Set-TransientPrompt"
         "prompt"
     )
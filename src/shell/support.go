@@ -0,0 +1,116 @@
+package shell
+
+import "slices"
+
+// Support describes a user facing feature and the shells supporting it.
+type Support struct {
+	// Notes holds the limitations of the feature in a shell.
+	Notes map[string]string
+	// Name is the name of the feature.
+	Name string
+	// Setting is the config setting enabling the feature.
+	Setting string
+	// Shells are the shells supporting the feature.
+	Shells []string
+	// Feature is the feature flag which enables the feature in the shell script, if any.
+	Feature Features
+}
+
+// Supports reports whether shell supports the feature.
+func (s *Support) Supports(shell string) bool {
+	return slices.Contains(s.Shells, shell)
+}
+
+// MatrixShells are the shells in the feature matrix, in the order they're listed.
+var MatrixShells = []string{BASH, ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH}
+
+// Matrix lists which features work in which shell.
+var Matrix = []*Support{
+	{
+		Name:    "transient prompt",
+		Setting: "transient_prompt",
+		Feature: Transient,
+		Shells:  []string{BASH, ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH},
+		Notes: map[string]string{
+			BASH:   "requires ble.sh",
+			ELVISH: "the prompt is replaced after accepting the command, wrapped lines aren't taken into account",
+			XONSH:  "the prompt is replaced before executing the command, wrapped lines aren't taken into account",
+		},
+	},
+	{
+		Name:    "tooltips",
+		Setting: "tooltips",
+		Feature: Tooltips,
+		Shells:  []string{ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH},
+		Notes: map[string]string{
+			XONSH: "replaces the right prompt",
+		},
+	},
+	{
+		Name:    "right prompt",
+		Setting: "rprompt block",
+		Feature: RPrompt,
+		Shells:  []string{BASH, ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH},
+		Notes: map[string]string{
+			BASH:  "requires ble.sh",
+			XONSH: "always displayed on the first line of the prompt",
+		},
+	},
+	{
+		Name:    "valid and error line",
+		Setting: "valid_line, error_line",
+		Feature: LineError,
+		Shells:  []string{PWSH},
+	},
+	{
+		Name:    "shell integration",
+		Setting: "shell_integration",
+		Feature: FTCSMarks,
+		Shells:  []string{BASH, ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH},
+		Notes: map[string]string{
+			CMD:    "requires Clink v1.14.25+",
+			NU:     "uses the built-in shell integration",
+			ELVISH: "the marks are written before the prompt, without OSC 133;B",
+			XONSH:  "the marks are written before the prompt, without OSC 133;B",
+		},
+	},
+	{
+		Name:    "working directory",
+		Setting: "pwd",
+		Feature: PWD,
+		Shells:  []string{BASH, ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH},
+		Notes: map[string]string{
+			ELVISH: "written before the prompt",
+			XONSH:  "written before the prompt",
+		},
+	},
+	{
+		Name:    "async",
+		Setting: "async",
+		Feature: Async,
+		Shells:  []string{BASH, ZSH, FISH, PWSH},
+		Notes: map[string]string{
+			NU:     "out of scope, the prompt is always rendered synchronously",
+			ELVISH: "out of scope, the prompt is always rendered synchronously",
+			XONSH:  "out of scope, the prompt is always rendered synchronously",
+		},
+	},
+	{
+		Name:    "cursor positioning",
+		Setting: "enable_cursor_positioning",
+		Feature: CursorPositioning,
+		Shells:  []string{BASH, ZSH},
+	},
+	{
+		Name:    "iTerm2 prompt mark",
+		Setting: "iterm_features",
+		Feature: PromptMark,
+		Shells:  []string{BASH, ZSH, FISH},
+	},
+	{
+		Name:    "upgrade notice",
+		Setting: "upgrade",
+		Feature: Upgrade,
+		Shells:  []string{BASH, ZSH, FISH, PWSH, CMD, NU, ELVISH, XONSH},
+	},
+}
@@ -0,0 +1,27 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix(t *testing.T) {
+	for _, support := range Matrix {
+		if support.Feature == 0 {
+			continue
+		}
+
+		for _, shell := range MatrixShells {
+			if len(support.Feature.Lines(shell)) == 0 {
+				continue
+			}
+
+			assert.True(t, support.Supports(shell), "%s has code for %s, but isn't listed as supported", shell, support.Name)
+		}
+
+		for shell := range support.Notes {
+			assert.Contains(t, MatrixShells, shell, support.Name)
+		}
+	}
+}
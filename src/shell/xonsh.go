@@ -15,7 +15,15 @@ func (f Features) Xonsh() Code {
 		return "@(_omp_executable) upgrade --auto"
 	case Notice:
 		return "@(_omp_executable) notice"
-	case PromptMark, RPrompt, PoshGit, Azure, LineError, Jobs, Tooltips, Transient, CursorPositioning, FTCSMarks, Async:
+	case FTCSMarks:
+		return "_omp_ftcs_marks = True"
+	case Transient:
+		return "_omp_transient_prompt = True"
+	case Tooltips:
+		return "enable_poshtooltips()"
+	case PWD:
+		return "_omp_pwd = True"
+	case PromptMark, RPrompt, PoshGit, Azure, LineError, Jobs, CursorPositioning, Async:
 		fallthrough
 	default:
 		return ""
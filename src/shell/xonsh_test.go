@@ -11,8 +11,12 @@ func TestXonshFeatures(t *testing.T) {
 	got := allFeatures.Lines(XONSH).String("// these are the features")
 
 	want := `// these are the features
+enable_poshtooltips()
+_omp_transient_prompt = True
+_omp_ftcs_marks = True
 @(_omp_executable) upgrade --auto
-@(_omp_executable) notice`
+@(_omp_executable) notice
+_omp_pwd = True`
 
 	assert.Equal(t, want, got)
 }
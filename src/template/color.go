@@ -0,0 +1,103 @@
+package template
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ResolveColor resolves palette references (p:name) for the color functions.
+// It's set once the config's palette is known, without it only hex and named colors work.
+var ResolveColor func(color string) (string, error)
+
+// rgb values for the named colors, using the xterm defaults
+var namedColors = map[string][3]uint8{
+	"black":        {0, 0, 0},
+	"red":          {205, 0, 0},
+	"green":        {0, 205, 0},
+	"yellow":       {205, 205, 0},
+	"blue":         {0, 0, 238},
+	"magenta":      {205, 0, 205},
+	"cyan":         {0, 205, 205},
+	"white":        {229, 229, 229},
+	"darkGray":     {127, 127, 127},
+	"lightRed":     {255, 0, 0},
+	"lightGreen":   {0, 255, 0},
+	"lightYellow":  {255, 255, 0},
+	"lightBlue":    {92, 92, 255},
+	"lightMagenta": {255, 0, 255},
+	"lightCyan":    {0, 255, 255},
+	"lightWhite":   {255, 255, 255},
+}
+
+func lighten(amount any, color string) (string, error) {
+	return mix("#ffffff", amount, color)
+}
+
+func darken(amount any, color string) (string, error) {
+	return mix("#000000", amount, color)
+}
+
+// mix blends the given amount of other into color, so it can be used in a pipeline
+// like {{ "p:red" | mix "p:blue" 0.3 }}. Amounts above 1 are treated as a percentage.
+func mix(other string, amount any, color string) (string, error) {
+	base, err := toRGB(color)
+	if err != nil {
+		return "", err
+	}
+
+	blend, err := toRGB(other)
+	if err != nil {
+		return "", err
+	}
+
+	weight := toFloat64(amount)
+	if weight > 1 {
+		weight /= 100
+	}
+
+	weight = math.Max(0, math.Min(1, weight))
+
+	var result [3]uint8
+	for i := range result {
+		result[i] = uint8(math.Round(float64(base[i])*(1-weight) + float64(blend[i])*weight))
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", result[0], result[1], result[2]), nil
+}
+
+func toRGB(color string) ([3]uint8, error) {
+	if strings.HasPrefix(color, "p:") {
+		if ResolveColor == nil {
+			return [3]uint8{}, fmt.Errorf("unable to resolve palette color %s", color)
+		}
+
+		resolved, err := ResolveColor(color)
+		if err != nil {
+			return [3]uint8{}, err
+		}
+
+		color = resolved
+	}
+
+	if rgb, OK := namedColors[color]; OK {
+		return rgb, nil
+	}
+
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if !strings.HasPrefix(color, "#") || len(hex) != 6 {
+		return [3]uint8{}, fmt.Errorf("unsupported color %s", color)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return [3]uint8{}, fmt.Errorf("unsupported color %s", color)
+	}
+
+	return [3]uint8{uint8(value >> 16), uint8(value >> 8), uint8(value)}, nil
+}
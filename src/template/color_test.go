@@ -0,0 +1,54 @@
+package template
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorFunctions(t *testing.T) {
+	ResolveColor = func(name string) (string, error) {
+		if name == "p:red" {
+			return "#ff0000", nil
+		}
+
+		return "", fmt.Errorf("unknown palette color %s", name)
+	}
+	defer func() { ResolveColor = nil }()
+
+	cases := []struct {
+		Case        string
+		Expected    string
+		Template    string
+		ShouldError bool
+	}{
+		{Case: "lighten hex", Expected: "#ff8080", Template: `{{ "#ff0000" | lighten 0.5 }}`},
+		{Case: "darken palette", Expected: "#cc0000", Template: `{{ "p:red" | darken 0.2 }}`},
+		{Case: "darken percentage", Expected: "#cc0000", Template: `{{ "p:red" | darken 20 }}`},
+		{Case: "mix", Expected: "#800080", Template: `{{ "p:red" | mix "#00f" 0.5 }}`},
+		{Case: "named color", Expected: "#000000", Template: `{{ "black" | lighten 0 }}`},
+		{Case: "unknown palette color", Template: `{{ "p:blue" | lighten 0.5 }}`, ShouldError: true},
+		{Case: "unsupported color", Template: `{{ "transparent" | lighten 0.5 }}`, ShouldError: true},
+	}
+
+	env := &mock.Environment{}
+	env.On("Shell").Return("foo")
+
+	Cache = new(cache.Template)
+	Init(env, nil, nil)
+
+	for _, tc := range cases {
+		text, err := Render(tc.Template, nil)
+		if tc.ShouldError {
+			assert.Error(t, err, tc.Case)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, text, tc.Case)
+	}
+}
@@ -0,0 +1,370 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/maps"
+)
+
+var (
+	// precompiled holds the patched template text, keyed by context type and raw template.
+	// It can be stored alongside the config so we only patch templates once.
+	precompiled = maps.NewConcurrent[string]()
+	// parsed holds the parsed templates for this process, keyed by patched template text.
+	parsed = maps.NewConcurrent[*template.Template]()
+)
+
+// Precompiled returns all templates patched so far, so they can be cached.
+func Precompiled() maps.Simple[string] {
+	return precompiled.ToSimple()
+}
+
+// SetPrecompiled restores previously patched templates, for example from the config cache.
+func SetPrecompiled(templates maps.Simple[string]) {
+	for key, value := range templates {
+		precompiled.Set(key, value)
+	}
+}
+
+// Compile patches the template for the given context and validates every field
+// reference against the context's type. The result is cached, so rendering the same
+// template with the same type of context later on skips patching it again.
+func Compile(tmpl string, context any) (string, error) {
+	return compile(tmpl, context, true)
+}
+
+func compile(tmpl string, context any, validate bool) (string, error) {
+	if !strings.Contains(tmpl, "{{") || !strings.Contains(tmpl, "}}") {
+		return tmpl, nil
+	}
+
+	key, cacheable := precompiledKey(tmpl, context)
+	if cacheable && !validate {
+		if patched, OK := precompiled.Get(key); OK {
+			return patched, nil
+		}
+	}
+
+	c := &compiler{
+		fields:      &fields{},
+		contextType: reflect.TypeOf(context),
+		validating:  validate,
+	}
+
+	c.fields.init(context)
+
+	patched, err := c.compile(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	if cacheable {
+		precompiled.Set(key, patched)
+	}
+
+	return patched, nil
+}
+
+// precompiledKey returns the cache key for a template and context.
+// Templates using a map as context can't be cached as the map's keys define how we patch it.
+func precompiledKey(tmpl string, context any) (string, bool) {
+	if context == nil {
+		return tmpl, true
+	}
+
+	contextType := reflect.TypeOf(context)
+	for contextType.Kind() == reflect.Pointer {
+		contextType = contextType.Elem()
+	}
+
+	if contextType.Kind() == reflect.Map {
+		return "", false
+	}
+
+	return contextType.String() + "|" + tmpl, true
+}
+
+type compiler struct {
+	fields      *fields
+	contextType reflect.Type
+	errors      []error
+	validating  bool
+}
+
+func (c *compiler) compile(text string) (string, error) {
+	text = replaceGlobalReferences(text)
+
+	tmpl, err := template.New("compile").Funcs(funcs()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Root == nil {
+			continue
+		}
+
+		c.walk(t.Root, true)
+
+		if t.Name() == tmpl.Name() {
+			continue
+		}
+
+		result.WriteString(fmt.Sprintf(`{{define %q}}%s{{end}}`, t.Name(), t.Root.String()))
+	}
+
+	c.addPartials(tmpl, &result)
+
+	if len(c.errors) != 0 {
+		return "", errors.Join(c.errors...)
+	}
+
+	if tmpl.Tree != nil && tmpl.Root != nil {
+		result.WriteString(tmpl.Root.String())
+	}
+
+	return result.String(), nil
+}
+
+// addPartials adds the user defined templates referenced by the template as definitions,
+// patched for the same context. Partials referencing other partials are added as well.
+func (c *compiler) addPartials(tmpl *template.Template, result *strings.Builder) {
+	var queue []string
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Root == nil {
+			continue
+		}
+
+		queue = append(queue, references(t.Root)...)
+	}
+
+	added := make(map[string]bool)
+
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		// templates defined in the template itself take precedence
+		if added[name] || tmpl.Lookup(name) != nil {
+			continue
+		}
+
+		tree, OK := partials[name]
+		if !OK {
+			if c.validating && name != "" {
+				c.errors = append(c.errors, fmt.Errorf("unknown template %s", name))
+			}
+
+			continue
+		}
+
+		added[name] = true
+
+		root := tree.Root.CopyList()
+		queue = append(queue, references(root)...)
+		c.walk(root, true)
+
+		result.WriteString(fmt.Sprintf(`{{define %q}}%s{{end}}`, name, root.String()))
+	}
+}
+
+// replaceGlobalReferences turns the .$ prefix, used to reference a global property
+// when the segment has a property with the same name, into the root variable.
+func replaceGlobalReferences(text string) string {
+	if !strings.Contains(text, ".$") {
+		return text
+	}
+
+	var result strings.Builder
+	var inTemplate bool
+
+	for i := 0; i < len(text); i++ {
+		if strings.HasPrefix(text[i:], "{{") {
+			inTemplate = true
+		}
+
+		if strings.HasPrefix(text[i:], "}}") {
+			inTemplate = false
+		}
+
+		if inTemplate && strings.HasPrefix(text[i:], ".$") && i > 0 && strings.ContainsRune(" {(", rune(text[i-1])) {
+			continue
+		}
+
+		result.WriteByte(text[i])
+	}
+
+	return result.String()
+}
+
+// walk patches all field references in the node. The root flag indicates
+// whether the dot still refers to the template's context, which is not
+// the case inside range and with blocks.
+func (c *compiler) walk(node parse.Node, root bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			c.walk(child, root)
+		}
+	case *parse.ActionNode:
+		c.walkPipe(n.Pipe, root)
+	case *parse.IfNode:
+		c.walkPipe(n.Pipe, root)
+		c.walk(n.List, root)
+		c.walk(n.ElseList, root)
+	case *parse.RangeNode:
+		c.walkPipe(n.Pipe, root)
+		c.walk(n.List, false)
+		c.walk(n.ElseList, root)
+	case *parse.WithNode:
+		c.walkPipe(n.Pipe, root)
+		c.walk(n.List, false)
+		c.walk(n.ElseList, root)
+	case *parse.TemplateNode:
+		c.walkPipe(n.Pipe, root)
+	}
+}
+
+func (c *compiler) walkPipe(pipe *parse.PipeNode, root bool) {
+	if pipe == nil {
+		return
+	}
+
+	for _, cmd := range pipe.Cmds {
+		for i, arg := range cmd.Args {
+			cmd.Args[i] = c.patch(arg, root)
+		}
+	}
+}
+
+func (c *compiler) patch(node parse.Node, root bool) parse.Node {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		return c.patchField(n, root)
+	case *parse.PipeNode:
+		c.walkPipe(n, root)
+	case *parse.ChainNode:
+		n.Node = c.patch(n.Node, root)
+	}
+
+	return node
+}
+
+func (c *compiler) patchField(field *parse.FieldNode, root bool) parse.Node {
+	idents := field.Ident
+
+	switch {
+	case idents[0] == "Segments" && len(idents) > 1 && idents[1] != "Contains":
+		// as we can't provide a clean way to access the list
+		// of segments, we need to replace the property with
+		// a lookup in the list of segments so they can be accessed directly
+		mustGet := newPipe(globalField(root, "Segments", "MustGet"), newString(idents[1]))
+		if len(idents) == 2 {
+			return mustGet
+		}
+
+		return &parse.ChainNode{NodeType: parse.NodeChain, Pos: field.Pos, Node: mustGet, Field: idents[2:]}
+	case idents[0] == "Env" && len(idents) > 1:
+		// we need to replace the property with the getEnv function
+		// so we can access the environment variables directly
+		name := strings.Join(idents[1:], ".")
+		return newPipe(&parse.IdentifierNode{NodeType: parse.NodeIdentifier, Ident: "call"}, globalField(root, "Getenv"), newString(name))
+	case !root:
+		return field
+	case c.fields.hasField(idents[0]):
+		// the segment's property takes precedence over the global one
+		c.validate(c.contextType, idents)
+		field.Ident = append([]string{"Data"}, idents...)
+		return field
+	default:
+		c.validate(reflect.TypeOf(&context{}), idents)
+		return field
+	}
+}
+
+func (c *compiler) validate(contextType reflect.Type, idents []string) {
+	if !c.validating || contextType == nil {
+		return
+	}
+
+	if err := resolveType(contextType, idents); err != nil {
+		c.errors = append(c.errors, err)
+	}
+}
+
+// resolveType validates the chain of field or method names against the given type.
+// Interfaces and maps can't be validated statically and are accepted as is.
+func resolveType(current reflect.Type, idents []string) error {
+	for _, ident := range idents {
+		if method, OK := current.MethodByName(ident); OK {
+			if method.Type.NumOut() == 0 {
+				return nil
+			}
+
+			current = method.Type.Out(0)
+			continue
+		}
+
+		for current.Kind() == reflect.Pointer {
+			current = current.Elem()
+		}
+
+		switch current.Kind() { //nolint:exhaustive
+		case reflect.Struct:
+			if method, OK := reflect.PointerTo(current).MethodByName(ident); OK {
+				if method.Type.NumOut() == 0 {
+					return nil
+				}
+
+				current = method.Type.Out(0)
+				continue
+			}
+
+			field, OK := current.FieldByName(ident)
+			if !OK || !field.IsExported() {
+				return fmt.Errorf("unknown field .%s in %s", strings.Join(idents, "."), current.String())
+			}
+
+			current = field.Type
+		case reflect.Map, reflect.Interface:
+			return nil
+		default:
+			return fmt.Errorf("unable to access .%s on %s", strings.Join(idents, "."), current.String())
+		}
+	}
+
+	return nil
+}
+
+func globalField(root bool, idents ...string) parse.Node {
+	if root {
+		return &parse.FieldNode{NodeType: parse.NodeField, Ident: idents}
+	}
+
+	return &parse.VariableNode{NodeType: parse.NodeVariable, Ident: append([]string{"$"}, idents...)}
+}
+
+func newString(value string) *parse.StringNode {
+	return &parse.StringNode{NodeType: parse.NodeString, Quoted: strconv.Quote(value), Text: value}
+}
+
+func newPipe(args ...parse.Node) *parse.PipeNode {
+	return &parse.PipeNode{
+		NodeType: parse.NodePipe,
+		Cmds: []*parse.CommandNode{
+			{NodeType: parse.NodeCommand, Args: args},
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compileContext struct {
+	Me      *compileMe
+	Labels  map[string]string
+	Name    string
+	Numbers []int
+}
+
+type compileMe struct {
+	Name string
+}
+
+func (c *compileMe) Greeting() string {
+	return "hello " + c.Name
+}
+
+func TestCompile(t *testing.T) {
+	cases := []struct {
+		Context     any
+		Case        string
+		Template    string
+		Expected    string
+		ShouldError bool
+	}{
+		{
+			Case:     "no template",
+			Template: "hello world",
+			Expected: "hello world",
+			Context:  &compileContext{},
+		},
+		{
+			Case:     "segment property",
+			Template: "{{ .Name }}",
+			Expected: "{{.Data.Name}}",
+			Context:  &compileContext{},
+		},
+		{
+			Case:     "global property",
+			Template: "{{ .Shell }} {{ .Var.Hello }}",
+			Expected: "{{.Shell}} {{.Var.Hello}}",
+			Context:  &compileContext{},
+		},
+		{
+			Case:     "nested property and method",
+			Template: "{{ .Me.Name }} {{ .Me.Greeting }}",
+			Expected: "{{.Data.Me.Name}} {{.Data.Me.Greeting}}",
+			Context:  &compileContext{},
+		},
+		{
+			Case:     "map property",
+			Template: "{{ .Labels.anything }}",
+			Expected: "{{.Data.Labels.anything}}",
+			Context:  &compileContext{},
+		},
+		{
+			Case:     "range keeps the dot",
+			Template: "{{ range .Numbers }}{{ .Name }}{{ end }}",
+			Expected: "{{range .Data.Numbers}}{{.Name}}{{end}}",
+			Context:  &compileContext{},
+		},
+		{
+			Case:     "env and segments in a with block",
+			Template: "{{ with .Me }}{{ .Env.HOME }}{{ .Segments.Git.HEAD }}{{ end }}",
+			Expected: `{{with .Data.Me}}{{(call $.Getenv "HOME")}}{{($.Segments.MustGet "Git").HEAD}}{{end}}`,
+			Context:  &compileContext{},
+		},
+		{
+			Case:        "unknown field",
+			Template:    "{{ .Nope }}",
+			Context:     &compileContext{},
+			ShouldError: true,
+		},
+		{
+			Case:        "unknown nested field",
+			Template:    "{{ .Me.Nope }}",
+			Context:     &compileContext{},
+			ShouldError: true,
+		},
+		{
+			Case:        "unknown global field",
+			Template:    "{{ .Nope }}",
+			ShouldError: true,
+		},
+		{
+			Case:        "invalid template",
+			Template:    "{{ if .Name }}",
+			Context:     &compileContext{},
+			ShouldError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		got, err := Compile(tc.Template, tc.Context)
+		if tc.ShouldError {
+			assert.Error(t, err, tc.Case)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, got, tc.Case)
+	}
+}
+
+func TestPrecompiled(t *testing.T) {
+	SetPrecompiled(map[string]string{
+		"template.compileMe|{{ .Cached }}": "{{.Data.Name}}",
+	})
+
+	got, err := compile("{{ .Cached }}", &compileMe{}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "{{.Data.Name}}", got)
+	assert.Equal(t, "{{.Data.Name}}", Precompiled()["template.compileMe|{{ .Cached }}"])
+}
@@ -2,31 +2,44 @@ package template
 
 import (
 	"path/filepath"
+	"sync"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
 )
 
+// funcs returns the function map, creating it only once as sprig's map is expensive to build.
+var funcs = sync.OnceValue(funcMap)
+
 func funcMap() template.FuncMap {
 	funcMap := map[string]any{
-		"secondsRound": secondsRound,
-		"url":          url,
-		"path":         filePath,
-		"glob":         glob,
-		"matchP":       matchP,
-		"findP":        findP,
-		"replaceP":     replaceP,
-		"gt":           gt,
-		"lt":           lt,
-		"random":       random,
-		"reason":       GetReasonFromStatus,
-		"hresult":      hresult,
-		"trunc":        trunc,
-		"truncE":       TruncE,
-		"readFile":     readFile,
-		"stat":         stat,
-		"dir":          filepath.Dir,
-		"base":         filepath.Base,
+		"secondsRound":   secondsRound,
+		"url":            url,
+		"path":           filePath,
+		"glob":           glob,
+		"matchP":         matchP,
+		"findP":          findP,
+		"replaceP":       replaceP,
+		"gt":             gt,
+		"lt":             lt,
+		"random":         random,
+		"reason":         GetReasonFromStatus,
+		"hresult":        hresult,
+		"trunc":          trunc,
+		"truncE":         TruncE,
+		"readFile":       readFile,
+		"stat":           stat,
+		"dir":            filepath.Dir,
+		"base":           filepath.Base,
+		"semverCompare":  semverCompare,
+		"humanizeBytes":  humanizeBytes,
+		"formatDuration": formatDuration,
+		"timeAgo":        timeAgo,
+		"percentBar":     percentBar,
+		"lighten":        lighten,
+		"darken":         darken,
+		"mix":            mix,
+		"include":        includeUnavailable,
 	}
 
 	for key, fun := range sprig.TxtFuncMap() {
@@ -0,0 +1,125 @@
+package template
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/duration"
+)
+
+// now is a variable so tests can control the current time
+var now = time.Now
+
+func humanizeBytes(value any) string {
+	bytes := toFloat64(value)
+	if bytes < 0 {
+		return "-" + humanizeBytes(-bytes)
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", int64(bytes), units[unit])
+	}
+
+	return fmt.Sprintf("%s %s", strconv.FormatFloat(math.Round(bytes*10)/10, 'f', -1, 64), units[unit])
+}
+
+// formatDuration uses the same styles as the executiontime segment.
+// The duration can be a time.Duration or a number of milliseconds.
+func formatDuration(style string, value any) string {
+	var ms int64
+
+	switch v := value.(type) {
+	case time.Duration:
+		ms = v.Milliseconds()
+	default:
+		ms = int64(toFloat64(value))
+	}
+
+	return duration.Format(ms, duration.Style(style))
+}
+
+// timeAgo returns how long ago a timestamp was, for example "3h ago".
+// Timestamps can be a time.Time, unix seconds or an RFC 3339 string.
+func timeAgo(value any) (string, error) {
+	var timestamp time.Time
+
+	switch v := value.(type) {
+	case time.Time:
+		timestamp = v
+	case *time.Time:
+		if v == nil {
+			return "", fmt.Errorf("timeAgo: timestamp is nil")
+		}
+
+		timestamp = *v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", err
+		}
+
+		timestamp = parsed
+	default:
+		seconds, err := toInt(value)
+		if err != nil {
+			return "", fmt.Errorf("timeAgo: unsupported timestamp %v", value)
+		}
+
+		timestamp = time.Unix(int64(seconds), 0)
+	}
+
+	elapsed := now().Sub(timestamp)
+
+	format := "%s ago"
+	if elapsed < 0 {
+		format = "in %s"
+		elapsed = -elapsed
+	}
+
+	if elapsed < time.Minute {
+		return "just now", nil
+	}
+
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"y", 365 * 24 * time.Hour},
+		{"mo", 30 * 24 * time.Hour},
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+	}
+
+	for _, unit := range units {
+		if elapsed >= unit.duration {
+			return fmt.Sprintf(format, fmt.Sprintf("%d%s", elapsed/unit.duration, unit.name)), nil
+		}
+	}
+
+	return "just now", nil
+}
+
+// percentBar renders a bar of the given width, for example ███░░░░░░░ for 30%.
+func percentBar(width int, percent any) string {
+	if width <= 0 {
+		return ""
+	}
+
+	value := math.Max(0, math.Min(100, toFloat64(percent)))
+	filled := int(math.Round(value / 100 * float64(width)))
+
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
@@ -0,0 +1,121 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanizeBytes(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Expected string
+		Template string
+	}{
+		{Case: "bytes", Expected: "512 B", Template: "{{ humanizeBytes 512 }}"},
+		{Case: "kibibytes", Expected: "1.5 KiB", Template: "{{ humanizeBytes 1536 }}"},
+		{Case: "gibibytes", Expected: "2 GiB", Template: "{{ humanizeBytes 2147483648 }}"},
+		{Case: "negative", Expected: "-1 MiB", Template: "{{ humanizeBytes -1048576 }}"},
+	}
+
+	env := &mock.Environment{}
+	env.On("Shell").Return("foo")
+
+	Cache = new(cache.Template)
+	Init(env, nil, nil)
+
+	for _, tc := range cases {
+		text, err := Render(tc.Template, nil)
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, text, tc.Case)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Expected string
+		Template string
+	}{
+		{Case: "austin", Expected: "3m 2.1s", Template: `{{ formatDuration "austin" 182100 }}`},
+		{Case: "round", Expected: "4h 3m", Template: `{{ 14582100 | formatDuration "round" }}`},
+		{Case: "galveston", Expected: "00:00:02", Template: `{{ formatDuration "galveston" 2100 }}`},
+		{Case: "unknown style", Expected: "Style: foo is not available", Template: `{{ formatDuration "foo" 2100 }}`},
+	}
+
+	env := &mock.Environment{}
+	env.On("Shell").Return("foo")
+
+	Cache = new(cache.Template)
+	Init(env, nil, nil)
+
+	for _, tc := range cases {
+		text, err := Render(tc.Template, nil)
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, text, tc.Case)
+	}
+
+	assert.Equal(t, "1.5s", formatDuration("austin", 1500*time.Millisecond))
+}
+
+func TestTimeAgo(t *testing.T) {
+	reference := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return reference }
+	defer func() { now = time.Now }()
+
+	cases := []struct {
+		Case        string
+		Expected    string
+		Timestamp   any
+		ShouldError bool
+	}{
+		{Case: "just now", Expected: "just now", Timestamp: reference.Add(-30 * time.Second)},
+		{Case: "hours", Expected: "3h ago", Timestamp: reference.Add(-3*time.Hour - 20*time.Minute)},
+		{Case: "days", Expected: "2d ago", Timestamp: reference.Add(-50 * time.Hour)},
+		{Case: "future", Expected: "in 5m", Timestamp: reference.Add(5 * time.Minute)},
+		{Case: "unix seconds", Expected: "1w ago", Timestamp: reference.Add(-8 * 24 * time.Hour).Unix()},
+		{Case: "RFC 3339", Expected: "1y ago", Timestamp: "2023-04-01T12:00:00Z"},
+		{Case: "invalid string", Timestamp: "yesterday", ShouldError: true},
+	}
+
+	for _, tc := range cases {
+		got, err := timeAgo(tc.Timestamp)
+		if tc.ShouldError {
+			assert.Error(t, err, tc.Case)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, got, tc.Case)
+	}
+}
+
+func TestPercentBar(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Expected string
+		Template string
+	}{
+		{Case: "empty", Expected: "░░░░░", Template: "{{ percentBar 5 0 }}"},
+		{Case: "partial", Expected: "███░░░░░░░", Template: "{{ 30 | percentBar 10 }}"},
+		{Case: "rounded", Expected: "███░░", Template: "{{ percentBar 5 55.5 }}"},
+		{Case: "overflow", Expected: "█████", Template: "{{ percentBar 5 120 }}"},
+		{Case: "no width", Expected: "", Template: "{{ percentBar 0 50 }}"},
+	}
+
+	env := &mock.Environment{}
+	env.On("Shell").Return("foo")
+
+	Cache = new(cache.Template)
+	Init(env, nil, nil)
+
+	for _, tc := range cases {
+		text, err := Render(tc.Template, nil)
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, text, tc.Case)
+	}
+}
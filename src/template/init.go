@@ -2,7 +2,6 @@ package template
 
 import (
 	"sync"
-	"text/template"
 
 	"github.com/jandedobbeleer/oh-my-posh/src/generics"
 	"github.com/jandedobbeleer/oh-my-posh/src/maps"
@@ -34,8 +33,7 @@ func Init(environment runtime.Environment, vars maps.Simple[any], aliases *maps.
 
 	renderPool = generics.NewPool(func() *renderer {
 		return &renderer{
-			template: template.New("cache").Funcs(funcMap()),
-			context:  &context{},
+			context: &context{},
 		}
 	})
 
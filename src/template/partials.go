@@ -0,0 +1,183 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// partials holds the parsed user defined templates, keyed by name.
+// They are added to every template referencing them and patched for that template's context.
+var partials map[string]*parse.Tree
+
+// SetPartials parses the user defined templates so every template can use them
+// with {{ template "name" . }} or {{ include "name" . }}. Partials that fail to parse,
+// reference an unknown partial or (indirectly) reference themselves are skipped.
+func SetPartials(templates map[string]string) []error {
+	var errs []error
+
+	trees := make(map[string]*parse.Tree, len(templates))
+
+	for name, text := range templates {
+		tmpl, err := template.New(name).Funcs(funcs()).Parse(replaceGlobalReferences(text))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", name, err))
+			continue
+		}
+
+		if len(tmpl.Templates()) > 1 {
+			errs = append(errs, fmt.Errorf("template %s: nested template definitions are not supported", name))
+			continue
+		}
+
+		if tmpl.Tree == nil || tmpl.Root == nil {
+			continue
+		}
+
+		trees[name] = tmpl.Tree
+	}
+
+	valid := make(map[string]*parse.Tree, len(trees))
+
+	for name, tree := range trees {
+		if err := validatePartial(name, tree, trees, nil); err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", name, err))
+			continue
+		}
+
+		valid[name] = tree
+	}
+
+	partials = valid
+
+	return errs
+}
+
+// validatePartial makes sure all references of a partial exist and don't lead back to a
+// partial we're already resolving, as that would recurse endlessly when rendering.
+func validatePartial(name string, tree *parse.Tree, trees map[string]*parse.Tree, chain []string) error {
+	chain = append(chain, name)
+
+	for _, reference := range references(tree.Root) {
+		for _, parent := range chain {
+			if parent == reference {
+				return fmt.Errorf("recursive reference: %s -> %s", strings.Join(chain, " -> "), reference)
+			}
+		}
+
+		if reference == "" {
+			return errors.New("include requires a literal template name")
+		}
+
+		child, OK := trees[reference]
+		if !OK {
+			return fmt.Errorf("unknown template %s", reference)
+		}
+
+		if err := validatePartial(reference, child, trees, chain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// references returns the names of all templates invoked in the node,
+// either using the template action or the include function.
+func references(node parse.Node) []string {
+	var names []string
+
+	var walkPipe func(pipe *parse.PipeNode)
+	var walk func(node parse.Node)
+
+	walkPipe = func(pipe *parse.PipeNode) {
+		if pipe == nil {
+			return
+		}
+
+		for _, cmd := range pipe.Cmds {
+			if name, OK := includeName(cmd); OK {
+				names = append(names, name)
+			}
+
+			for _, arg := range cmd.Args {
+				if p, OK := arg.(*parse.PipeNode); OK {
+					walkPipe(p)
+				}
+			}
+		}
+	}
+
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walkPipe(n.Pipe)
+		case *parse.IfNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			names = append(names, n.Name)
+			walkPipe(n.Pipe)
+		}
+	}
+
+	walk(node)
+
+	return names
+}
+
+// includeName returns the template name when the command is a call to include.
+// The name is empty when it isn't a literal, which we can't validate.
+func includeName(cmd *parse.CommandNode) (string, bool) {
+	if len(cmd.Args) < 2 {
+		return "", false
+	}
+
+	identifier, OK := cmd.Args[0].(*parse.IdentifierNode)
+	if !OK || identifier.Ident != "include" {
+		return "", false
+	}
+
+	name, OK := cmd.Args[1].(*parse.StringNode)
+	if !OK {
+		return "", true
+	}
+
+	return name.Text, true
+}
+
+// include renders a template of the set as a string, so it can be used in a pipeline.
+func include(tmpl *template.Template) func(string, any) (string, error) {
+	return func(name string, data any) (string, error) {
+		var builder strings.Builder
+
+		if err := tmpl.ExecuteTemplate(&builder, name, data); err != nil {
+			return "", err
+		}
+
+		return builder.String(), nil
+	}
+}
+
+func includeUnavailable(string, any) (string, error) {
+	return "", errors.New("include is only available when rendering a template")
+}
@@ -0,0 +1,157 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPartials(t *testing.T) {
+	cases := []struct {
+		Templates      map[string]string
+		Case           string
+		ExpectedError  string
+		ExpectedValid  []string
+		ExpectedErrors int
+	}{
+		{
+			Case:          "valid partials",
+			Templates:     map[string]string{"icon": "", "name": `{{ template "icon" . }} {{ .Name }}`},
+			ExpectedValid: []string{"icon", "name"},
+		},
+		{
+			Case:           "parse error",
+			Templates:      map[string]string{"broken": "{{ if .Name }}", "icon": ""},
+			ExpectedValid:  []string{"icon"},
+			ExpectedErrors: 1,
+			ExpectedError:  "template broken:",
+		},
+		{
+			Case:           "self reference",
+			Templates:      map[string]string{"loop": `{{ template "loop" . }}`},
+			ExpectedErrors: 1,
+			ExpectedError:  "recursive reference: loop -> loop",
+		},
+		{
+			Case:           "indirect reference using include",
+			Templates:      map[string]string{"a": `{{ include "b" . }}`, "b": `{{ template "a" . }}`},
+			ExpectedErrors: 2,
+			ExpectedError:  "recursive reference",
+		},
+		{
+			Case:           "unknown reference",
+			Templates:      map[string]string{"a": `{{ template "b" . }}`},
+			ExpectedErrors: 1,
+			ExpectedError:  "unknown template b",
+		},
+		{
+			Case:           "dynamic include",
+			Templates:      map[string]string{"a": `{{ include .Name . }}`},
+			ExpectedErrors: 1,
+			ExpectedError:  "include requires a literal template name",
+		},
+		{
+			Case:           "nested definition",
+			Templates:      map[string]string{"a": `{{ define "b" }}b{{ end }}a`},
+			ExpectedErrors: 1,
+			ExpectedError:  "nested template definitions are not supported",
+		},
+	}
+
+	for _, tc := range cases {
+		errs := SetPartials(tc.Templates)
+
+		assert.Len(t, errs, tc.ExpectedErrors, tc.Case)
+		for _, err := range errs {
+			assert.ErrorContains(t, err, tc.ExpectedError, tc.Case)
+		}
+
+		assert.Len(t, partials, len(tc.ExpectedValid), tc.Case)
+		for _, name := range tc.ExpectedValid {
+			assert.Contains(t, partials, name, tc.Case)
+		}
+	}
+
+	SetPartials(nil)
+}
+
+func TestRenderPartials(t *testing.T) {
+	env := &mock.Environment{}
+	env.On("Shell").Return("foo")
+
+	Cache = new(cache.Template)
+	Cache.OS = "linux"
+	Init(env, nil, nil)
+
+	SetPartials(map[string]string{
+		"os":    "{{ .OS }}",
+		"label": `{{ .Name }} on {{ template "os" . }}`,
+	})
+	defer SetPartials(nil)
+
+	cases := []struct {
+		Context     any
+		Case        string
+		Template    string
+		Expected    string
+		ShouldError bool
+	}{
+		{
+			Case:     "template action",
+			Template: `{{ template "label" . }}`,
+			Expected: "posh on linux",
+			Context:  &compileContext{Name: "posh"},
+		},
+		{
+			Case:     "include in a pipeline",
+			Template: `{{ include "label" . | upper }}`,
+			Expected: "POSH ON LINUX",
+			Context:  &compileContext{Name: "posh"},
+		},
+		{
+			Case:     "local definition takes precedence",
+			Template: `{{ define "os" }}windows{{ end }}{{ template "label" . }}`,
+			Expected: "posh on windows",
+			Context:  &compileContext{Name: "posh"},
+		},
+		{
+			Case:     "map context",
+			Template: `{{ template "label" . }}`,
+			Expected: "map on linux",
+			Context:  map[string]any{"Name": "map"},
+		},
+		{
+			Case:        "unknown template",
+			Template:    `{{ template "nope" . }}`,
+			Context:     &compileContext{},
+			ShouldError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		text, err := Render(tc.Template, tc.Context)
+		if tc.ShouldError {
+			assert.Error(t, err, tc.Case)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, text, tc.Case)
+	}
+}
+
+func TestCompilePartials(t *testing.T) {
+	SetPartials(map[string]string{
+		"name": "{{ .Name }} {{ .Unknown }}",
+	})
+	defer SetPartials(nil)
+
+	_, err := Compile(`{{ template "name" . }}`, &compileContext{})
+	assert.ErrorContains(t, err, "unknown field .Unknown")
+
+	_, err = Compile(`{{ template "missing" . }}`, &compileContext{})
+	assert.ErrorContains(t, err, "unknown template missing")
+}
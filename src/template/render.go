@@ -30,20 +30,37 @@ var (
 )
 
 type renderer struct {
-	template *template.Template
-	context  *context
-	buffer   bytes.Buffer
+	context *context
+	buffer  bytes.Buffer
 }
 
 func (t *renderer) release() {
 	t.buffer.Reset()
 	t.context.Data = nil
-	t.template.New("cache")
 	renderPool.Put(t)
 }
 
+// parseTemplate returns the parsed template, parsing every patched template only once per process.
+func parseTemplate(text string) (*template.Template, error) {
+	if tmpl, OK := parsed.Get(text); OK {
+		return tmpl, nil
+	}
+
+	tmpl, err := template.New("cache").Funcs(funcs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// include needs to know the templates it can render
+	tmpl.Funcs(template.FuncMap{"include": include(tmpl)})
+
+	parsed.Set(text, tmpl)
+
+	return tmpl, nil
+}
+
 func (t *renderer) execute(text *Text) (string, error) {
-	tmpl, err := t.template.Parse(text.template)
+	tmpl, err := parseTemplate(text.template)
 	if err != nil {
 		log.Error(err)
 		return "", errors.New(InvalidTemplate)
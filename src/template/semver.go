@@ -0,0 +1,59 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// semverCompare checks a version against a constraint like ">= 3.10".
+// Next to plain strings, it accepts a segment's Version struct so
+// {{ semverCompare ">=20" .Version }} works without having to use .Full.
+func semverCompare(constraint string, version any) (bool, error) {
+	value, err := versionString(version)
+	if err != nil {
+		return false, err
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := semver.NewVersion(value)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Check(v), nil
+}
+
+func versionString(version any) (string, error) {
+	switch v := version.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	value := reflect.ValueOf(version)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", fmt.Errorf("semverCompare: version is nil")
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return "", fmt.Errorf("semverCompare: unsupported version type %T", version)
+	}
+
+	full := value.FieldByName("Full")
+	if !full.IsValid() || full.Kind() != reflect.String {
+		return "", fmt.Errorf("semverCompare: %T has no Full version", version)
+	}
+
+	return full.String(), nil
+}
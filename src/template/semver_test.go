@@ -0,0 +1,53 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/cache"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type semverVersion struct {
+	Full  string
+	Major string
+}
+
+type semverContext struct {
+	Version semverVersion
+}
+
+func TestSemverCompare(t *testing.T) {
+	cases := []struct {
+		Case        string
+		Expected    string
+		Template    string
+		Context     any
+		ShouldError bool
+	}{
+		{Case: "string", Expected: "true", Template: `{{ semverCompare ">= 1.2" "1.10.0" }}`},
+		{Case: "string - no match", Expected: "false", Template: `{{ semverCompare "^2" "1.10.0" }}`},
+		{Case: "version struct", Expected: "true", Template: `{{ semverCompare ">=3.10" .Version }}`, Context: &semverContext{Version: semverVersion{Full: "3.11.4"}}},
+		{Case: "version struct - prerelease", Expected: "false", Template: `{{ semverCompare ">=20" .Version }}`, Context: &semverContext{Version: semverVersion{Full: "20.0.0-rc.1"}}},
+		{Case: "invalid constraint", Template: `{{ semverCompare "foo" "1.0.0" }}`, ShouldError: true},
+		{Case: "unsupported type", Template: `{{ semverCompare "1.0.0" 1 }}`, ShouldError: true},
+	}
+
+	env := &mock.Environment{}
+	env.On("Shell").Return("foo")
+
+	Cache = new(cache.Template)
+	Init(env, nil, nil)
+
+	for _, tc := range cases {
+		text, err := Render(tc.Template, tc.Context)
+		if tc.ShouldError {
+			assert.Error(t, err, tc.Case)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, text, tc.Case)
+	}
+}
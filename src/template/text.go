@@ -1,7 +1,7 @@
 package template
 
 import (
-	"fmt"
+	"errors"
 	"reflect"
 	"strings"
 	"sync"
@@ -37,7 +37,10 @@ func Render(template string, context any) (string, error) {
 		return t.template, nil
 	}
 
-	t.patchTemplate()
+	if err := t.patchTemplate(); err != nil {
+		log.Error(err)
+		return "", errors.New(InvalidTemplate)
+	}
 
 	renderer := renderPool.Get()
 	defer renderer.release()
@@ -55,98 +58,17 @@ func (t *Text) release() {
 	}
 }
 
-func (t *Text) patchTemplate() {
-	fields := &fields{}
-	fields.init(t.context)
-
-	var result, property string
-	var inProperty, inTemplate bool
-	for i, char := range t.template {
-		// define start or end of template
-		if !inTemplate && char == '{' {
-			if i-1 >= 0 && rune(t.template[i-1]) == '{' {
-				inTemplate = true
-			}
-		} else if inTemplate && char == '}' {
-			if i-1 >= 0 && rune(t.template[i-1]) == '}' {
-				inTemplate = false
-			}
-		}
-
-		if !inTemplate {
-			result += string(char)
-			continue
-		}
-
-		switch char {
-		case '.':
-			var lastChar rune
-			if len(result) > 0 {
-				lastChar = rune(result[len(result)-1])
-			}
-			// only replace if we're in a valid property start
-			// with a space, { or ( character
-			switch lastChar {
-			case ' ', '{', '(':
-				property += string(char)
-				inProperty = true
-			default:
-				result += string(char)
-			}
-		case ' ', '}', ')': // space or }
-			if !inProperty {
-				result += string(char)
-				continue
-			}
-
-			switch {
-			case strings.HasPrefix(property, ".Segments") && !strings.HasSuffix(property, ".Contains"):
-				// as we can't provide a clean way to access the list
-				// of segments, we need to replace the property with
-				// the list of segments so they can be accessed directly
-				parts := strings.Split(property, ".")
-				if len(parts) > 3 {
-					property = fmt.Sprintf(`(.Segments.MustGet "%s").%s`, parts[2], strings.Join(parts[3:], "."))
-				} else {
-					property = fmt.Sprintf(`(.Segments.MustGet "%s")`, parts[2])
-				}
-				result += property
-				// property = strings.Replace(property, ".Segments", ".Segments.ToSimple", 1)
-				// result += property
-			case strings.HasPrefix(property, ".Env."):
-				// we need to replace the property with the getEnv function
-				// so we can access the environment variables directly
-				property = strings.TrimPrefix(property, ".Env.")
-				result += fmt.Sprintf(`(call .Getenv "%s")`, property)
-			default:
-				// check if we have the same property in Data
-				// and replace it with the Data property so it
-				// can take precedence
-				if fields.hasField(property) {
-					property = ".Data" + property
-				}
-
-				// remove the global reference so we can use it directly
-				property = strings.TrimPrefix(property, globalRef)
-				result += property
-			}
-
-			property = ""
-			result += string(char)
-			inProperty = false
-		default:
-			if inProperty {
-				property += string(char)
-				continue
-			}
-			result += string(char)
-		}
+func (t *Text) patchTemplate() error {
+	patched, err := compile(t.template, t.context, false)
+	if err != nil {
+		return err
 	}
 
-	// return the result and remaining unresolved property
-	t.template = result + property
+	t.template = patched
 
 	log.Debug(t.template)
+
+	return nil
 }
 
 type fields struct {
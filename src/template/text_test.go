@@ -278,17 +278,17 @@ func TestPatchTemplate(t *testing.T) {
 		},
 		{
 			Case:     "Variable",
-			Expected: "{{range $cpu := .Data.CPU}}{{round $cpu.Mhz 2 }} {{end}}",
+			Expected: "{{range $cpu := .Data.CPU}}{{round $cpu.Mhz 2}} {{end}}",
 			Template: "{{range $cpu := .CPU}}{{round $cpu.Mhz 2 }} {{end}}",
 		},
 		{
 			Case:     "Same prefix",
-			Expected: "{{ (call .Getenv \"HELLO\") }} {{ .Data.World }} {{ .Data.WorldTrend }}",
+			Expected: "{{(call .Getenv \"HELLO\")}} {{.Data.World}} {{.Data.WorldTrend}}",
 			Template: "{{ .Env.HELLO }} {{ .World }} {{ .WorldTrend }}",
 		},
 		{
 			Case:     "Double use of property with different child",
-			Expected: "{{ (call .Getenv \"HELLO\") }} {{ .Data.World.Trend }} {{ .Data.World.Hello }} {{ .Data.World }}",
+			Expected: "{{(call .Getenv \"HELLO\")}} {{.Data.World.Trend}} {{.Data.World.Hello}} {{.Data.World}}",
 			Template: "{{ .Env.HELLO }} {{ .World.Trend }} {{ .World.Hello }} {{ .World }}",
 		},
 		{
@@ -303,17 +303,17 @@ func TestPatchTemplate(t *testing.T) {
 		},
 		{
 			Case:     "Multiple vars with spaces",
-			Expected: "{{ (call .Getenv \"HELLO\") }} {{ .Data.World }} {{ .Data.World }}",
+			Expected: "{{(call .Getenv \"HELLO\")}} {{.Data.World}} {{.Data.World}}",
 			Template: "{{ .Env.HELLO }} {{ .World }} {{ .World }}",
 		},
 		{
 			Case:     "Braces",
-			Expected: "{{ if or (.Data.Working.Changed) (.Data.Staging.Changed) }}#FF9248{{ end }}",
+			Expected: "{{if or (.Data.Working.Changed) (.Data.Staging.Changed)}}#FF9248{{end}}",
 			Template: "{{ if or (.Working.Changed) (.Staging.Changed) }}#FF9248{{ end }}",
 		},
 		{
 			Case:     "Global property override",
-			Expected: "{{.OS}}",
+			Expected: "{{$.OS}}",
 			Template: "{{.$.OS}}",
 		},
 		{
@@ -353,7 +353,8 @@ func TestPatchTemplate(t *testing.T) {
 			context:  context,
 		}
 
-		tmpl.patchTemplate()
+		err := tmpl.patchTemplate()
+		assert.NoError(t, err, tc.Case)
 		assert.Equal(t, tc.Expected, tmpl.template, tc.Case)
 	}
 }
@@ -375,8 +376,9 @@ func TestPatchTemplateStruct(t *testing.T) {
 		context:  Foo{},
 	}
 
-	tmpl.patchTemplate()
-	assert.Equal(t, "{{ .Data.Hello }}", tmpl.template)
+	err := tmpl.patchTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, "{{.Data.Hello}}", tmpl.template)
 }
 
 func TestSegmentContains(t *testing.T) {
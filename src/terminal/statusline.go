@@ -0,0 +1,407 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/text"
+)
+
+const (
+	Tmux    = "tmux"
+	Zellij  = "zellij"
+	WezTerm = "wezterm"
+)
+
+// StatusLineTargets lists the status line formats we can convert a rendered block to.
+var StatusLineTargets = []string{Tmux, Zellij, WezTerm}
+
+var basicColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// statusColor is a color parsed from an SGR sequence.
+// Index is -1 for true colors and -2 for the terminal's default color.
+type statusColor struct {
+	Hex   string
+	Index int
+}
+
+func (c *statusColor) isDefault() bool {
+	return c.Index == -2
+}
+
+type statusAttribute string
+
+const (
+	attrBold          statusAttribute = "bold"
+	attrDim           statusAttribute = "dim"
+	attrItalic        statusAttribute = "italic"
+	attrUnderline     statusAttribute = "underline"
+	attrBlink         statusAttribute = "blink"
+	attrReverse       statusAttribute = "reverse"
+	attrStrikethrough statusAttribute = "strikethrough"
+	attrOverline      statusAttribute = "overline"
+)
+
+// statusDirective is a single style change, either a color, an attribute (on or off) or a full reset.
+type statusDirective struct {
+	Foreground *statusColor
+	Background *statusColor
+	Attribute  statusAttribute
+	Off        bool
+	Reset      bool
+}
+
+type statusLineWriter interface {
+	style(directives []statusDirective)
+	text(txt string)
+	String() string
+}
+
+// StatusLine converts a rendered ANSI string into the native format of the given status line target.
+// Unsupported escape sequences (hyperlinks, cursor movements, ...) are dropped.
+func StatusLine(target, txt string) (string, error) {
+	var writer statusLineWriter
+
+	switch target {
+	case Tmux:
+		writer = &tmuxWriter{builder: text.NewBuilder()}
+	case Zellij:
+		writer = &tmuxWriter{builder: text.NewBuilder(), zellij: true}
+	case WezTerm:
+		writer = &wezTermWriter{}
+	default:
+		return "", fmt.Errorf("unsupported status line target: %s", target)
+	}
+
+	runes := []rune(txt)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			writer.text(string(runes[start:end]))
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\x1b' || i+1 >= len(runes) {
+			continue
+		}
+
+		flush(i)
+
+		switch runes[i+1] {
+		case '[':
+			end := i + 2
+			for end < len(runes) && (runes[end] < 0x40 || runes[end] > 0x7e) {
+				end++
+			}
+
+			if end < len(runes) && runes[end] == 'm' {
+				writer.style(parseSGR(string(runes[i+2 : end])))
+			}
+
+			i = end
+		case ']':
+			// OSC sequences end with BEL or ST (ESC \)
+			end := i + 2
+			for end < len(runes) {
+				if runes[end] == '\a' {
+					break
+				}
+
+				if runes[end] == '\x1b' && end+1 < len(runes) && runes[end+1] == '\\' {
+					end++
+					break
+				}
+
+				end++
+			}
+
+			i = end
+		default:
+			i++
+		}
+
+		start = i + 1
+	}
+
+	flush(len(runes))
+
+	return writer.String(), nil
+}
+
+func parseSGR(sequence string) []statusDirective {
+	if sequence == "" {
+		return []statusDirective{{Reset: true}}
+	}
+
+	params := strings.Split(sequence, ";")
+	directives := make([]statusDirective, 0, len(params))
+
+	attributes := map[int]statusAttribute{
+		1: attrBold, 2: attrDim, 3: attrItalic, 4: attrUnderline,
+		5: attrBlink, 7: attrReverse, 9: attrStrikethrough, 53: attrOverline,
+	}
+
+	resets := map[int][]statusAttribute{
+		22: {attrBold, attrDim}, 23: {attrItalic}, 24: {attrUnderline},
+		25: {attrBlink}, 27: {attrReverse}, 29: {attrStrikethrough}, 55: {attrOverline},
+	}
+
+	for i := 0; i < len(params); i++ {
+		code, err := strconv.Atoi(params[i])
+		if err != nil {
+			continue
+		}
+
+		if attr, ok := attributes[code]; ok {
+			directives = append(directives, statusDirective{Attribute: attr})
+			continue
+		}
+
+		if attrs, ok := resets[code]; ok {
+			for _, attr := range attrs {
+				directives = append(directives, statusDirective{Attribute: attr, Off: true})
+			}
+
+			continue
+		}
+
+		switch {
+		case code == 0:
+			directives = append(directives, statusDirective{Reset: true})
+		case code >= 30 && code <= 37:
+			directives = append(directives, statusDirective{Foreground: &statusColor{Index: code - 30}})
+		case code >= 90 && code <= 97:
+			directives = append(directives, statusDirective{Foreground: &statusColor{Index: code - 90 + 8}})
+		case code >= 40 && code <= 47:
+			directives = append(directives, statusDirective{Background: &statusColor{Index: code - 40}})
+		case code >= 100 && code <= 107:
+			directives = append(directives, statusDirective{Background: &statusColor{Index: code - 100 + 8}})
+		case code == 39:
+			directives = append(directives, statusDirective{Foreground: &statusColor{Index: -2}})
+		case code == 49:
+			directives = append(directives, statusDirective{Background: &statusColor{Index: -2}})
+		case code == 38 || code == 48:
+			c, consumed := parseExtendedColor(params[i+1:])
+			i += consumed
+
+			if c == nil {
+				continue
+			}
+
+			if code == 38 {
+				directives = append(directives, statusDirective{Foreground: c})
+				continue
+			}
+
+			directives = append(directives, statusDirective{Background: c})
+		}
+	}
+
+	return directives
+}
+
+// parseExtendedColor parses the parameters following a 38 or 48 SGR code
+// and returns the color together with the number of parameters consumed.
+func parseExtendedColor(params []string) (*statusColor, int) {
+	if len(params) == 0 {
+		return nil, 0
+	}
+
+	switch params[0] {
+	case "5":
+		if len(params) < 2 {
+			return nil, len(params)
+		}
+
+		index, err := strconv.Atoi(params[1])
+		if err != nil {
+			return nil, 2
+		}
+
+		return &statusColor{Index: index}, 2
+	case "2":
+		if len(params) < 4 {
+			return nil, len(params)
+		}
+
+		rgb := make([]int, 3)
+		for i := range rgb {
+			value, err := strconv.Atoi(params[i+1])
+			if err != nil {
+				return nil, 4
+			}
+
+			rgb[i] = value
+		}
+
+		return &statusColor{Index: -1, Hex: fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])}, 4
+	default:
+		return nil, 1
+	}
+}
+
+// tmuxWriter writes #[fg=...,bg=...] style directives, as used by tmux and zjstatus for zellij.
+type tmuxWriter struct {
+	builder *text.StringBuilder
+	zellij  bool
+}
+
+func (t *tmuxWriter) color(c *statusColor) string {
+	switch {
+	case c.isDefault():
+		return "default"
+	case c.Index == -1:
+		return c.Hex
+	case t.zellij:
+		return strconv.Itoa(c.Index)
+	case c.Index < 8:
+		return basicColorNames[c.Index]
+	case c.Index < 16:
+		return "bright" + basicColorNames[c.Index-8]
+	default:
+		return fmt.Sprintf("colour%d", c.Index)
+	}
+}
+
+func (t *tmuxWriter) attribute(attr statusAttribute, off bool) string {
+	name := string(attr)
+
+	if !t.zellij {
+		switch attr { //nolint:exhaustive
+		case attrItalic:
+			name = "italics"
+		case attrUnderline:
+			name = "underscore"
+		}
+	}
+
+	if off {
+		return "no" + name
+	}
+
+	return name
+}
+
+func (t *tmuxWriter) style(directives []statusDirective) {
+	if len(directives) == 0 {
+		return
+	}
+
+	parts := make([]string, 0, len(directives))
+
+	for _, directive := range directives {
+		switch {
+		case directive.Reset:
+			parts = append(parts, "default")
+		case directive.Foreground != nil:
+			parts = append(parts, "fg="+t.color(directive.Foreground))
+		case directive.Background != nil:
+			parts = append(parts, "bg="+t.color(directive.Background))
+		case len(directive.Attribute) != 0:
+			parts = append(parts, t.attribute(directive.Attribute, directive.Off))
+		}
+	}
+
+	t.builder.WriteString("#[")
+	t.builder.WriteString(strings.Join(parts, ","))
+	t.builder.WriteString("]")
+}
+
+func (t *tmuxWriter) text(txt string) {
+	// # starts a format directive, so it needs to be escaped
+	t.builder.WriteString(strings.ReplaceAll(txt, "#", "##"))
+}
+
+func (t *tmuxWriter) String() string {
+	return t.builder.String()
+}
+
+// wezTermWriter writes a JSON array of FormatItems which can be passed to wezterm.format
+// after decoding it with wezterm.json_parse.
+type wezTermWriter struct {
+	items []any
+}
+
+func (w *wezTermWriter) color(c *statusColor) any {
+	ansiNames := []string{"Black", "Maroon", "Green", "Olive", "Navy", "Purple", "Teal", "Silver",
+		"Grey", "Red", "Lime", "Yellow", "Blue", "Fuchsia", "Aqua", "White"}
+
+	switch {
+	case c.Index == -1:
+		return map[string]string{"Color": c.Hex}
+	case c.Index < 16:
+		return map[string]string{"AnsiColor": ansiNames[c.Index]}
+	default:
+		return map[string]int{"PaletteIndex": c.Index}
+	}
+}
+
+func (w *wezTermWriter) style(directives []statusDirective) {
+	for _, directive := range directives {
+		switch {
+		case directive.Reset:
+			w.items = append(w.items, "ResetAttributes")
+		case directive.Foreground != nil && directive.Foreground.isDefault():
+			w.items = append(w.items, map[string]string{"Foreground": "Default"})
+		case directive.Foreground != nil:
+			w.items = append(w.items, map[string]any{"Foreground": w.color(directive.Foreground)})
+		case directive.Background != nil && directive.Background.isDefault():
+			w.items = append(w.items, map[string]string{"Background": "Default"})
+		case directive.Background != nil:
+			w.items = append(w.items, map[string]any{"Background": w.color(directive.Background)})
+		case len(directive.Attribute) != 0:
+			if attribute := w.attribute(directive.Attribute, directive.Off); attribute != nil {
+				w.items = append(w.items, map[string]any{"Attribute": attribute})
+			}
+		}
+	}
+}
+
+func (w *wezTermWriter) attribute(attr statusAttribute, off bool) any {
+	switch attr { //nolint:exhaustive
+	case attrBold:
+		if off {
+			return map[string]string{"Intensity": "Normal"}
+		}
+
+		return map[string]string{"Intensity": "Bold"}
+	case attrDim:
+		if off {
+			return map[string]string{"Intensity": "Normal"}
+		}
+
+		return map[string]string{"Intensity": "Half"}
+	case attrItalic:
+		return map[string]bool{"Italic": !off}
+	case attrUnderline:
+		if off {
+			return map[string]string{"Underline": "None"}
+		}
+
+		return map[string]string{"Underline": "Single"}
+	default:
+		// not supported by wezterm.format
+		return nil
+	}
+}
+
+func (w *wezTermWriter) text(txt string) {
+	w.items = append(w.items, map[string]string{"Text": txt})
+}
+
+func (w *wezTermWriter) String() string {
+	if len(w.items) == 0 {
+		return "[]"
+	}
+
+	data, err := json.Marshal(w.items)
+	if err != nil {
+		return "[]"
+	}
+
+	return string(data)
+}
@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusLine(t *testing.T) {
+	cases := []struct {
+		Case          string
+		Target        string
+		Input         string
+		Expected      string
+		ExpectedError bool
+	}{
+		{
+			Case:     "tmux - plain text",
+			Target:   Tmux,
+			Input:    "hello world",
+			Expected: "hello world",
+		},
+		{
+			Case:     "tmux - escape hash",
+			Target:   Tmux,
+			Input:    "#42",
+			Expected: "##42",
+		},
+		{
+			Case:     "tmux - basic colors",
+			Target:   Tmux,
+			Input:    "\x1b[41m\x1b[30mtest\x1b[0m",
+			Expected: "#[bg=red]#[fg=black]test#[default]",
+		},
+		{
+			Case:     "tmux - bright and 256 colors",
+			Target:   Tmux,
+			Input:    "\x1b[101;38;5;202mtest",
+			Expected: "#[bg=brightred,fg=colour202]test",
+		},
+		{
+			Case:     "tmux - true color",
+			Target:   Tmux,
+			Input:    "\x1b[48;2;255;87;51m\x1b[38;2;255;255;255mtest\x1b[49m",
+			Expected: "#[bg=#ff5733]#[fg=#ffffff]test#[bg=default]",
+		},
+		{
+			Case:     "tmux - styles",
+			Target:   Tmux,
+			Input:    "\x1b[1m\x1b[3mtest\x1b[23m\x1b[22m",
+			Expected: "#[bold]#[italics]test#[noitalics]#[nobold,nodim]",
+		},
+		{
+			Case:     "tmux - transparent",
+			Target:   Tmux,
+			Input:    "\x1b[0m\x1b[31;49m\x1b[7m\x1b[27m",
+			Expected: "#[default]#[fg=red,bg=default]#[reverse]#[noreverse]",
+		},
+		{
+			Case:     "tmux - drop hyperlinks",
+			Target:   Tmux,
+			Input:    "\x1b]8;;https://ohmyposh.dev\x1b\\link\x1b]8;;\x1b\\",
+			Expected: "link",
+		},
+		{
+			Case:     "zellij - colors",
+			Target:   Zellij,
+			Input:    "\x1b[41m\x1b[38;2;255;255;255m\x1b[4mtest",
+			Expected: "#[bg=1]#[fg=#ffffff]#[underline]test",
+		},
+		{
+			Case:     "wezterm - colors",
+			Target:   WezTerm,
+			Input:    "\x1b[41m\x1b[38;2;255;255;255m\x1b[1mtest\x1b[0m",
+			Expected: `[{"Background":{"AnsiColor":"Maroon"}},{"Foreground":{"Color":"#ffffff"}},{"Attribute":{"Intensity":"Bold"}},{"Text":"test"},"ResetAttributes"]`,
+		},
+		{
+			Case:     "wezterm - empty",
+			Target:   WezTerm,
+			Expected: "[]",
+		},
+		{
+			Case:          "unknown target",
+			Target:        "screen",
+			Input:         "test",
+			ExpectedError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		got, err := StatusLine(tc.Target, tc.Input)
+		if tc.ExpectedError {
+			assert.Error(t, err, tc.Case)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Case)
+		assert.Equal(t, tc.Expected, got, tc.Case)
+	}
+}
@@ -241,6 +241,36 @@ func CommandFinished(code int, ignore bool) string {
 	return fmt.Sprintf(formats.Escape, mark)
 }
 
+// CurrentDirectory reports pwd using the OSC 633 Cwd property, used by the shell integration.
+func CurrentDirectory(pwd string) string {
+	if Plain {
+		return ""
+	}
+
+	mark := fmt.Sprintf("\x1b]633;P;Cwd=%s\007", EscapeProperty(pwd))
+
+	return fmt.Sprintf(formats.Escape, mark)
+}
+
+// EscapeProperty escapes the value of an OSC 633 property, backslashes, semicolons
+// and control characters are written as their \xAB hex value.
+func EscapeProperty(value string) string {
+	var sb strings.Builder
+
+	for _, char := range value {
+		switch {
+		case char == '\\':
+			sb.WriteString(`\\`)
+		case char == ';' || char < 0x20 || char == 0x7f:
+			fmt.Fprintf(&sb, `\x%02x`, char)
+		default:
+			sb.WriteRune(char)
+		}
+	}
+
+	return sb.String()
+}
+
 func LineBreak() string {
 	cr := fmt.Sprintf(formats.Left, 1000)
 	lf := fmt.Sprintf(formats.Linechange, 1, "B")
@@ -294,3 +294,21 @@ func TestWriteLength(t *testing.T) {
 		assert.Equal(t, tc.Expected, got, tc.Case)
 	}
 }
+
+func TestEscapeProperty(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Value    string
+		Expected string
+	}{
+		{Case: "Plain", Value: "git status", Expected: "git status"},
+		{Case: "Backslash", Value: `C:\Users\posh`, Expected: `C:\\Users\\posh`},
+		{Case: "Semicolon", Value: "cd ..; ls", Expected: `cd ..\x3b ls`},
+		{Case: "Newline", Value: "echo a\necho b", Expected: `echo a\x0aecho b`},
+		{Case: "Unicode", Value: "echo 🚀", Expected: "echo 🚀"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.Expected, EscapeProperty(tc.Value), tc.Case)
+	}
+}
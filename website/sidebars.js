@@ -47,6 +47,7 @@ module.exports = {
         "configuration/transient",
         "configuration/line-error",
         "configuration/tooltips",
+        "configuration/status-line",
         "configuration/sample",
       ],
     },
@@ -135,6 +136,7 @@ module.exports = {
             "segments/languages/java",
             "segments/languages/julia",
             "segments/languages/kotlin",
+            "segments/languages/language",
             "segments/languages/lua",
             "segments/languages/mojo",
             "segments/languages/nim",
@@ -184,6 +186,7 @@ module.exports = {
             "segments/system/battery",
             "segments/system/command",
             "segments/system/connection",
+            "segments/system/container",
             "segments/system/executiontime",
             "segments/system/os",
             "segments/system/path",